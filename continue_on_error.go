@@ -0,0 +1,46 @@
+package plugins
+
+import (
+	"fmt"
+	"io/fs"
+)
+
+var _ FSSetable = ContinueOnError{}
+var _ IOSetable = ContinueOnError{}
+var _ Needer = ContinueOnError{}
+
+// ContinueOnError wraps a Plugins collection so that
+// SetStdio, SetFileSystem, and WithPlugins attempt every
+// plugin instead of stopping at the first failure. The
+// returned error is an errors.Join of *PluginError values.
+// If the dependencies can not be sorted, see Sorted, the
+// plugins are tried in collection order, and the sort
+// error is joined with theirs.
+//
+//	err := plugins.ContinueOnError(plugs).SetStdio(io)
+//
+//	var perr *plugins.PluginError
+//	if errors.As(err, &perr) {
+//		// perr.Name, perr.Type, perr.Op
+//	}
+type ContinueOnError Plugins
+
+func (c ContinueOnError) PluginName() string {
+	return fmt.Sprintf("%T", c)
+}
+
+// SetStdio for every plugin that implements IOSetable.
+func (c ContinueOnError) SetStdio(io IO) error {
+	return Plugins(c).setStdio(io, true)
+}
+
+// SetFileSystem for every plugin that implements FSSetable.
+func (c ContinueOnError) SetFileSystem(fs fs.FS) error {
+	return Plugins(c).setFileSystem(fs, true)
+}
+
+// WithPlugins calls every Needer plugin with the
+// Feeder function.
+func (c ContinueOnError) WithPlugins(fn FeederFn) error {
+	return Plugins(c).withPlugins(fn, true)
+}
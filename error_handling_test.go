@@ -145,3 +145,124 @@ func (p *AvailablePlugin) PluginName() string {
 func (p *AvailablePlugin) PluginAvailable(root string) bool {
 	return p.available
 }
+
+func Test_ContinueOnError_SetStdio(t *testing.T) {
+	t.Parallel()
+	r := require.New(t)
+
+	good := &plugtest.IO{}
+	plugs := Plugins{
+		&FailingIOPlugin{},
+		good,
+	}
+
+	io := iox.Discard()
+
+	// the default mode stops at the first failure
+	err := plugs.SetStdio(io)
+	r.Error(err)
+	r.Nil(good.IO.Out)
+
+	err = ContinueOnError(plugs).SetStdio(io)
+	r.Error(err)
+	r.Equal(io, good.IO)
+
+	var perr *PluginError
+	r.True(errors.As(err, &perr))
+	r.Equal("failing-io-plugin", perr.Name)
+	r.Equal("*plugins_test.FailingIOPlugin", perr.Type)
+	r.Equal(OpSetStdio, perr.Op)
+	r.Contains(err.Error(), "failed to set stdio for plugin failing-io-plugin")
+}
+
+func Test_ContinueOnError_SetFileSystem(t *testing.T) {
+	t.Parallel()
+	r := require.New(t)
+
+	good := &plugtest.FSable{}
+	plugs := ContinueOnError{
+		&FailingFSPlugin{},
+		good,
+		&FailingFSPlugin{},
+	}
+
+	fsys := fstest.MapFS{}
+	err := plugs.SetFileSystem(fsys)
+	r.Error(err)
+	r.Equal(fsys, good.FS)
+
+	joined, ok := err.(interface{ Unwrap() []error })
+	r.True(ok)
+	r.Len(joined.Unwrap(), 2)
+
+	for _, e := range joined.Unwrap() {
+		var perr *PluginError
+		r.True(errors.As(e, &perr))
+		r.Equal(OpSetFileSystem, perr.Op)
+		r.Equal("failing-fs-plugin", perr.Name)
+	}
+
+	r.Error(plugs.SetFileSystem(nil))
+}
+
+func Test_ContinueOnError_WithPlugins(t *testing.T) {
+	t.Parallel()
+	r := require.New(t)
+
+	good := &plugtest.Needer{}
+	plugs := ContinueOnError{
+		&FailingNeederPlugin{},
+		good,
+	}
+
+	fn := func() Plugins { return Plugins(plugs) }
+	err := plugs.WithPlugins(fn)
+	r.Error(err)
+	r.NotNil(good.Fn)
+
+	var perr *PluginError
+	r.True(errors.As(err, &perr))
+	r.Equal(OpWithPlugins, perr.Op)
+	r.Contains(err.Error(), "failed to set plugins for needer failing-needer-plugin")
+
+	r.NoError(ContinueOnError{good}.WithPlugins(fn))
+}
+
+func Test_ContinueOnError_Unsorted(t *testing.T) {
+	t.Parallel()
+	r := require.New(t)
+
+	io := &plugtest.IO{}
+	fsp := &plugtest.FSable{}
+	needer := &plugtest.Needer{}
+	plugs := ContinueOnError{
+		requirer{name: "app", required: []string{"db"}},
+		&FailingIOPlugin{},
+		io,
+		fsp,
+		needer,
+	}
+
+	// the default mode stops at the sort error
+	r.Error(Plugins(plugs).SetStdio(iox.Discard()))
+	r.Nil(io.IO.Out)
+
+	err := plugs.SetStdio(iox.Discard())
+	r.Error(err)
+	r.Contains(err.Error(), "plugin app requires missing plugin db")
+	r.NotNil(io.IO.Out)
+
+	var perr *PluginError
+	r.True(errors.As(err, &perr))
+	r.Equal(OpSetStdio, perr.Op)
+
+	fsys := fstest.MapFS{}
+	err = plugs.SetFileSystem(fsys)
+	r.Error(err)
+	r.Contains(err.Error(), "plugin app requires missing plugin db")
+	r.Equal(fsys, fsp.FS)
+
+	err = plugs.WithPlugins(func() Plugins { return Plugins(plugs) })
+	r.Error(err)
+	r.NotNil(needer.Fn)
+}
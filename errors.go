@@ -0,0 +1,93 @@
+package plugins
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+)
+
+// Operations reported by PluginError.
+const (
+	OpSetStdio      = "SetStdio"
+	OpSetFileSystem = "SetFileSystem"
+	OpWithPlugins   = "WithPlugins"
+	OpInit          = "Init"
+	OpStart         = "Start"
+	OpStop          = "Stop"
+)
+
+var opMessages = map[string]string{
+	OpSetStdio:      "set stdio for plugin",
+	OpSetFileSystem: "set filesystem for plugin",
+	OpWithPlugins:   "set plugins for needer",
+	OpInit:          "init plugin",
+	OpStart:         "start plugin",
+	OpStop:          "stop plugin",
+}
+
+// PluginError records an error returned by a plugin
+// during an operation on a Plugins collection. Use
+// errors.As to find out which plugin failed.
+type PluginError struct {
+	Name string // the plugin's name
+	Type string // the plugin's Go type
+	Op   string // the operation that failed, e.g. OpSetStdio
+	Err  error  // the error returned by the plugin
+}
+
+// NewPluginError returns a PluginError for the
+// given operation, plugin, and error.
+func NewPluginError(op string, p Plugin, err error) *PluginError {
+	return &PluginError{
+		Name: p.PluginName(),
+		Type: fmt.Sprintf("%T", p),
+		Op:   op,
+		Err:  err,
+	}
+}
+
+func (e *PluginError) Error() string {
+	msg, ok := opMessages[e.Op]
+	if !ok {
+		msg = fmt.Sprintf("%s plugin", e.Op)
+	}
+	return fmt.Sprintf("failed to %s %s: %v", msg, e.Name, e.Err)
+}
+
+func (e *PluginError) Unwrap() error {
+	return e.Err
+}
+
+// LogValue implements slog.LogValuer.
+func (e *PluginError) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("plugin", e.Name),
+		slog.String("type", e.Type),
+		slog.String("op", e.Op),
+		slog.Any("error", e.Err),
+	)
+}
+
+// each calls fn for each plugin. By default it stops at
+// the first error. If cont is true, every plugin is tried
+// and all errors are returned joined together.
+func each[T Plugin](plugs []T, op string, cont bool, fn func(p T) error) error {
+	var errs []error
+
+	for _, p := range plugs {
+		err := fn(p)
+		if err == nil {
+			continue
+		}
+
+		perr := NewPluginError(op, p, err)
+		slog.Error("plugin operation failed", "error", perr)
+
+		if !cont {
+			return perr
+		}
+		errs = append(errs, perr)
+	}
+
+	return errors.Join(errs...)
+}
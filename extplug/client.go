@@ -0,0 +1,355 @@
+package extplug
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/markbates/plugins"
+)
+
+var _ plugins.IOSetable = &Client{}
+var _ plugins.Scoper = &Client{}
+var _ plugins.Stopper = &Client{}
+
+// ErrClosed is returned by calls made after the
+// connection to the child has been closed.
+var ErrClosed = errors.New("extplug: connection closed")
+
+// CallTimeout bounds the calls a Client makes to the child
+// on its own: the handshake, when its context has no
+// deadline, availability checks, and stopping the child
+// after a failed handshake.
+var CallTimeout = 10 * time.Second
+
+// Client is the host side of a connection to a child
+// process serving plugins.
+type Client struct {
+	name  string
+	r     io.Reader
+	w     io.WriteCloser
+	cmd   *exec.Cmd
+	plugs plugins.Plugins
+
+	oi      plugins.IO
+	pending map[int64]chan message
+	nextID  atomic.Int64
+	done    chan struct{}
+	err     error
+	stopErr error
+
+	mu   sync.Mutex
+	wmu  sync.Mutex
+	stop sync.Once
+}
+
+// Load starts the executable at path with the given
+// arguments and performs the handshake. The context is
+// only used for the handshake, use Stop to end the child.
+// Without a deadline, the handshake is bounded by
+// CallTimeout.
+func Load(ctx context.Context, path string, args ...string) (*Client, error) {
+	cmd := exec.Command(path, args...)
+
+	w, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+
+	c := newClient(filepath.Base(path), w)
+	cmd.Stderr = writerFn(func(b []byte) (int, error) {
+		return c.stdio().Stderr().Write(b)
+	})
+	c.cmd = cmd
+
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+
+	go c.read(r)
+
+	if err := c.handshake(ctx); err != nil {
+		c.abort(ctx)
+		return nil, fmt.Errorf("handshake with %s failed: %w", path, err)
+	}
+
+	return c, nil
+}
+
+// Connect performs the handshake over an already
+// established connection, such as a pair of pipes.
+// If r is an io.Closer it is closed by Stop.
+func Connect(ctx context.Context, name string, r io.Reader, w io.WriteCloser) (*Client, error) {
+	c := newClient(name, w)
+	c.r = r
+
+	go c.read(r)
+
+	if err := c.handshake(ctx); err != nil {
+		c.abort(ctx)
+		return nil, fmt.Errorf("handshake with %s failed: %w", name, err)
+	}
+
+	return c, nil
+}
+
+func newClient(name string, w io.WriteCloser) *Client {
+	return &Client{
+		name:    name,
+		w:       w,
+		pending: map[int64]chan message{},
+		done:    make(chan struct{}),
+	}
+}
+
+// PluginName implements plugins.Plugin.
+func (c *Client) PluginName() string {
+	return c.name
+}
+
+// ScopedPlugins returns a plugin for each plugin
+// the child advertised in the handshake.
+func (c *Client) ScopedPlugins() plugins.Plugins {
+	return c.plugs
+}
+
+// SetStdio sets the IO that output from the
+// child's plugins is forwarded to.
+func (c *Client) SetStdio(oi plugins.IO) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.oi = oi
+	return nil
+}
+
+func (c *Client) stdio() plugins.IO {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.oi
+}
+
+// Stop closes the connection to the child and waits for
+// it to exit. If the context is done first, the child
+// is killed.
+func (c *Client) Stop(ctx context.Context) error {
+	c.stop.Do(func() {
+		c.stopErr = c.close(ctx)
+	})
+	return c.stopErr
+}
+
+func (c *Client) close(ctx context.Context) error {
+	c.w.Close()
+
+	if cl, ok := c.r.(io.Closer); ok {
+		cl.Close()
+	}
+
+	var err error
+	select {
+	case <-c.done:
+	case <-ctx.Done():
+		err = ctx.Err()
+		if c.cmd != nil {
+			c.cmd.Process.Kill()
+		}
+	}
+
+	if c.cmd == nil {
+		return err
+	}
+
+	if werr := c.cmd.Wait(); werr != nil && err == nil {
+		err = werr
+	}
+
+	return err
+}
+
+// abort stops the child after a failed handshake. The
+// handshake may have failed because ctx is done, so the
+// child is given CallTimeout to exit instead.
+func (c *Client) abort(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), CallTimeout)
+	defer cancel()
+
+	c.Stop(ctx)
+}
+
+func (c *Client) handshake(ctx context.Context) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	var hs Handshake
+	if err := c.call(ctx, methodHandshake, nil, &hs); err != nil {
+		return err
+	}
+
+	if err := hs.validate(); err != nil {
+		return err
+	}
+
+	plugs := make(plugins.Plugins, 0, len(hs.Plugins))
+	for _, info := range hs.Plugins {
+		plugs = append(plugs, newProxy(c, info))
+	}
+
+	c.plugs = plugs
+	return nil
+}
+
+// withTimeout bounds ctx by CallTimeout,
+// unless it has a deadline of its own.
+func withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if _, ok := ctx.Deadline(); ok {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, CallTimeout)
+}
+
+func (c *Client) call(ctx context.Context, method string, params any, res any) error {
+	id := c.nextID.Add(1)
+
+	msg := message{
+		ID:     &id,
+		Method: method,
+	}
+
+	if params != nil {
+		b, err := json.Marshal(params)
+		if err != nil {
+			return err
+		}
+		msg.Params = b
+	}
+
+	ch := make(chan message, 1)
+
+	c.mu.Lock()
+	if c.err != nil {
+		c.mu.Unlock()
+		return c.err
+	}
+	c.pending[id] = ch
+	c.mu.Unlock()
+
+	defer func() {
+		c.mu.Lock()
+		delete(c.pending, id)
+		c.mu.Unlock()
+	}()
+
+	if err := c.send(msg); err != nil {
+		return err
+	}
+
+	select {
+	case resp := <-ch:
+		if resp.Error != nil {
+			return resp.Error
+		}
+		if res == nil || len(resp.Result) == 0 {
+			return nil
+		}
+		return json.Unmarshal(resp.Result, res)
+	case <-ctx.Done():
+		b, _ := json.Marshal(cancelParams{ID: id})
+		c.send(message{Method: methodCancel, Params: b})
+		return ctx.Err()
+	case <-c.done:
+		return c.err
+	}
+}
+
+func (c *Client) send(msg message) error {
+	msg.JSONRPC = "2.0"
+
+	b, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	c.wmu.Lock()
+	defer c.wmu.Unlock()
+
+	_, err = c.w.Write(append(b, '\n'))
+	return err
+}
+
+func (c *Client) read(r io.Reader) {
+	scan := bufio.NewScanner(r)
+	scan.Buffer(make([]byte, 0, 64*1024), maxLine)
+
+	for scan.Scan() {
+		line := scan.Bytes()
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+
+		var msg message
+		if err := json.Unmarshal(line, &msg); err != nil {
+			continue
+		}
+
+		switch msg.Method {
+		case methodStdout, methodStderr:
+			c.output(msg)
+			continue
+		}
+
+		if msg.ID == nil {
+			continue
+		}
+
+		c.mu.Lock()
+		ch, ok := c.pending[*msg.ID]
+		c.mu.Unlock()
+
+		if ok {
+			ch <- msg
+		}
+	}
+
+	err := scan.Err()
+	if err == nil {
+		err = ErrClosed
+	}
+
+	c.mu.Lock()
+	c.err = err
+	c.mu.Unlock()
+
+	close(c.done)
+}
+
+func (c *Client) output(msg message) {
+	var op outputParams
+	if err := json.Unmarshal(msg.Params, &op); err != nil {
+		return
+	}
+
+	oi := c.stdio()
+
+	var w io.Writer = oi.Stdout()
+	if msg.Method == methodStderr {
+		w = oi.Stderr()
+	}
+
+	w.Write(op.Data)
+}
@@ -0,0 +1,155 @@
+// Package extplug runs plugins in a separate process and
+// talks to them over a line-delimited JSON-RPC 2.0 protocol
+// on the child's stdin and stdout.
+//
+// The host launches an executable with Load. The child
+// answers a handshake describing each plugin it serves,
+// and the host returns a plugins.Plugin for each one that
+// implements the interfaces the child advertised.
+//
+//	c, err := extplug.Load(ctx, "./bin/my-plugins")
+//	if err != nil {
+//		return err
+//	}
+//	defer c.Stop(ctx)
+//
+//	plugs = append(plugs, c.ScopedPlugins()...)
+//
+// The child side is a Go program that serves a
+// plugins.Plugins collection:
+//
+//	func main() {
+//		plugs := plugins.Plugins{
+//			&BuildCmd{},
+//		}
+//
+//		if err := extplug.Serve(context.Background(), plugs); err != nil {
+//			log.Fatal(err)
+//		}
+//	}
+//
+// Commander and Flagger are only implemented by the host
+// side plugin when the child advertises them. Namer,
+// Describer, Aliaser, and AvailabilityChecker are always
+// implemented, and behave as if missing when they were
+// not advertised.
+//
+// Output written by a served command to its stdio is
+// forwarded to the IO set on the host side plugin.
+// Standard input is not forwarded.
+package extplug
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Version is the protocol version spoken by this package.
+const Version = 1
+
+// Interface names advertised in the handshake.
+const (
+	IfaceCommander           = "Commander"
+	IfaceNamer               = "Namer"
+	IfaceDescriber           = "Describer"
+	IfaceAliaser             = "Aliaser"
+	IfaceFlagger             = "Flagger"
+	IfaceAvailabilityChecker = "AvailabilityChecker"
+)
+
+// Methods of the protocol.
+const (
+	methodHandshake = "plugin.handshake"
+	methodAvailable = "plugin.available"
+	methodMain      = "plugin.main"
+	methodCancel    = "$/cancel"
+	methodStdout    = "io.stdout"
+	methodStderr    = "io.stderr"
+)
+
+// Handshake is the child's answer to the
+// handshake request.
+type Handshake struct {
+	Version int          `json:"version"`
+	Plugins []PluginInfo `json:"plugins"`
+}
+
+// PluginInfo describes a single plugin served by the
+// child, along with the interfaces it implements.
+type PluginInfo struct {
+	Name        string     `json:"name"`
+	Interfaces  []string   `json:"interfaces"`
+	CmdName     string     `json:"cmd_name,omitempty"`
+	Description string     `json:"description,omitempty"`
+	Aliases     []string   `json:"aliases,omitempty"`
+	Flags       []FlagInfo `json:"flags,omitempty"`
+}
+
+// FlagInfo describes a single flag of a Flagger.
+type FlagInfo struct {
+	Name    string `json:"name"`
+	Usage   string `json:"usage,omitempty"`
+	Default string `json:"default,omitempty"`
+	Bool    bool   `json:"bool,omitempty"`
+}
+
+// Error is a JSON-RPC error. Errors returned by plugins
+// in the child are reported to the host as an *Error.
+type Error struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// Error codes used by the protocol.
+const (
+	CodeParseError     = -32700
+	CodeMethodNotFound = -32601
+	CodeInvalidParams  = -32602
+	CodePluginError    = -32000
+)
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+type message struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      *int64          `json:"id,omitempty"`
+	Method  string          `json:"method,omitempty"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *Error          `json:"error,omitempty"`
+}
+
+type availableParams struct {
+	Plugin string `json:"plugin"`
+	Root   string `json:"root"`
+}
+
+type mainParams struct {
+	Plugin string   `json:"plugin"`
+	Root   string   `json:"root"`
+	Args   []string `json:"args"`
+}
+
+type cancelParams struct {
+	ID int64 `json:"id"`
+}
+
+type outputParams struct {
+	Data []byte `json:"data"`
+}
+
+func (hs Handshake) validate() error {
+	if hs.Version != Version {
+		return fmt.Errorf("unsupported protocol version %d", hs.Version)
+	}
+
+	for _, p := range hs.Plugins {
+		if p.Name == "" {
+			return fmt.Errorf("plugin with empty name in handshake")
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,289 @@
+package extplug
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/markbates/iox"
+	"github.com/markbates/plugins"
+	"github.com/markbates/plugins/plugcmd"
+	"github.com/markbates/plugins/plugtest"
+	"github.com/stretchr/testify/require"
+)
+
+var helperBin string
+
+func TestMain(m *testing.M) {
+	dir, err := os.MkdirTemp("", "extplug")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	helperBin = filepath.Join(dir, "helper")
+	if runtime.GOOS == "windows" {
+		helperBin += ".exe"
+	}
+
+	build := exec.Command("go", "build", "-o", helperBin, "./testdata/helper")
+	build.Stdout = os.Stdout
+	build.Stderr = os.Stderr
+	if err := build.Run(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	code := m.Run()
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+func load(t *testing.T) (*Client, *iox.Buffer) {
+	t.Helper()
+	r := require.New(t)
+
+	ctx := context.Background()
+	c, err := Load(ctx, helperBin)
+	r.NoError(err)
+	t.Cleanup(func() {
+		r.NoError(c.Stop(ctx))
+	})
+
+	bb := &iox.Buffer{}
+	r.NoError(c.ScopedPlugins().SetStdio(bb.IO()))
+
+	return c, bb
+}
+
+func Test_Load_Handshake(t *testing.T) {
+	t.Parallel()
+	r := require.New(t)
+
+	c, _ := load(t)
+
+	plugs := c.ScopedPlugins()
+	r.Equal([]string{"helper/echo", "wait", "avail"}, plugs.Names())
+
+	echo := plugcmd.Find("echo", plugs)
+	r.NotNil(echo)
+	r.Equal(echo, plugcmd.Find("e", plugs))
+
+	d, ok := echo.(plugcmd.Describer)
+	r.True(ok)
+	r.Equal("echoes its arguments", d.Description())
+
+	f, ok := echo.(plugcmd.Flagger)
+	r.True(ok)
+	set, err := f.Flags()
+	r.NoError(err)
+	r.Len(plugcmd.SetToSlice(set), 2)
+	r.Equal(">", set.Lookup("prefix").DefValue)
+
+	i, ok := echo.(Interfaceser)
+	r.True(ok)
+	r.Contains(i.Interfaces(), IfaceFlagger)
+
+	// avail is not a Commander or a Flagger
+	av := plugs[2]
+	_, ok = av.(plugcmd.Commander)
+	r.False(ok)
+	_, ok = av.(plugcmd.Flagger)
+	r.False(ok)
+
+	w := plugcmd.Find("wait", plugs)
+	r.NotNil(w)
+	_, ok = w.(plugcmd.Flagger)
+	r.False(ok)
+}
+
+func Test_Load_Main(t *testing.T) {
+	t.Parallel()
+	r := require.New(t)
+
+	c, bb := load(t)
+
+	echo := plugcmd.Find("echo", c.ScopedPlugins())
+	r.NotNil(echo)
+
+	err := echo.Main(context.Background(), "/root", []string{"-upper", "hello", "world"})
+	r.NoError(err)
+	r.Equal("> /root HELLO WORLD\n", bb.Out.String())
+	r.Equal("done\n", bb.Err.String())
+
+	err = echo.Main(context.Background(), "/root", []string{"fail"})
+	r.Error(err)
+
+	var rerr *Error
+	r.True(errors.As(err, &rerr))
+	r.Equal(CodePluginError, rerr.Code)
+	r.Equal("asked to fail", rerr.Message)
+}
+
+func Test_Load_Cancel(t *testing.T) {
+	t.Parallel()
+	r := require.New(t)
+
+	c, _ := load(t)
+
+	w := plugcmd.Find("wait", c.ScopedPlugins())
+	r.NotNil(w)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	err := w.Main(ctx, "", nil)
+	r.ErrorIs(err, context.DeadlineExceeded)
+
+	// the connection is still usable
+	echo := plugcmd.Find("echo", c.ScopedPlugins())
+	r.NoError(echo.Main(context.Background(), "", []string{"again"}))
+}
+
+func Test_Load_Available(t *testing.T) {
+	t.Parallel()
+	r := require.New(t)
+
+	c, _ := load(t)
+
+	plugs := c.ScopedPlugins()
+	r.Len(plugs.Available("/ok"), 3)
+	r.Len(plugs.Available("/nope"), 2)
+}
+
+func Test_Load_Missing(t *testing.T) {
+	t.Parallel()
+	r := require.New(t)
+
+	_, err := Load(context.Background(), filepath.Join(t.TempDir(), "missing"))
+	r.Error(err)
+}
+
+func Test_Connect(t *testing.T) {
+	t.Parallel()
+	r := require.New(t)
+
+	hr, cw := io.Pipe()
+	cr, hw := io.Pipe()
+
+	plugs := plugins.Plugins{
+		plugcmd.CommanderFn(func(ctx context.Context, root string, args []string) error {
+			return fmt.Errorf("root: %s", root)
+		}),
+	}
+
+	errc := make(chan error, 1)
+	go func() {
+		errc <- ServeConn(context.Background(), plugs, cr, cw)
+	}()
+
+	ctx := context.Background()
+	c, err := Connect(ctx, "pipes", hr, hw)
+	r.NoError(err)
+	r.Equal("pipes", c.PluginName())
+
+	cmds := plugins.ByType[plugcmd.Commander](c.ScopedPlugins())
+	r.Len(cmds, 1)
+
+	err = cmds[0].Main(ctx, "/here", nil)
+	r.Error(err)
+	r.Equal("root: /here", err.Error())
+
+	r.NoError(c.Stop(ctx))
+	r.NoError(<-errc)
+
+	err = cmds[0].Main(ctx, "/here", nil)
+	r.Error(err)
+}
+
+type stalledAvail chan struct{}
+
+func (s stalledAvail) PluginName() string {
+	return "stalled"
+}
+
+func (s stalledAvail) PluginAvailable(root string) bool {
+	<-s
+	return true
+}
+
+func Test_Connect_CallTimeout(t *testing.T) {
+	orig := CallTimeout
+	CallTimeout = 50 * time.Millisecond
+	t.Cleanup(func() { CallTimeout = orig })
+
+	r := require.New(t)
+
+	ctx := context.Background()
+
+	// a child that never answers the handshake
+	hr, cw := io.Pipe()
+	cr, hw := io.Pipe()
+	go io.Copy(io.Discard, cr)
+
+	_, err := Connect(ctx, "stalled", hr, hw)
+	r.ErrorIs(err, context.DeadlineExceeded)
+	cw.Close()
+
+	// a child that never answers an availability check
+	hr, cw = io.Pipe()
+	cr, hw = io.Pipe()
+
+	release := make(stalledAvail)
+	errc := make(chan error, 1)
+	go func() {
+		errc <- ServeConn(ctx, plugins.Plugins{release}, cr, cw)
+	}()
+
+	c, err := Connect(ctx, "stalled", hr, hw)
+	r.NoError(err)
+
+	r.Empty(c.ScopedPlugins().Available("/"))
+
+	close(release)
+	r.NoError(c.Stop(ctx))
+	r.NoError(<-errc)
+}
+
+func Test_Load_Run_Flags(t *testing.T) {
+	r := require.New(t)
+
+	c, bb := load(t)
+
+	plugs := append(plugins.Plugins{c}, c.ScopedPlugins()...)
+	plugs = append(plugs, &plugtest.IO{IO: bb.IO()})
+
+	ctx := context.Background()
+	r.NoError(plugcmd.Run(ctx, "/root", []string{"echo", "--prefix=#", "-upper", "hi"}, plugs))
+	r.Equal("# /root HI\n", bb.Out.String())
+
+	// flags bound on the host
+	env := plugcmd.EnvName([]string{filepath.Base(os.Args[0]), "echo"}, "prefix")
+	t.Setenv(env, "$")
+
+	bb.Out.Reset()
+	r.NoError(plugcmd.Run(ctx, "/root", []string{"echo", "hi"}, plugs))
+	r.Equal("$ /root hi\n", bb.Out.String())
+
+	// parsed on the host, Main given only the positional args
+	echo := plugcmd.Find("echo", plugs)
+	set, err := echo.(plugcmd.Flagger).Flags()
+	r.NoError(err)
+	r.NoError(set.Parse([]string{"-upper", "--prefix", "%", "hi"}))
+
+	bb.Out.Reset()
+	r.NoError(echo.Main(ctx, "/root", set.Args()))
+	r.Equal("% /root HI\n", bb.Out.String())
+
+	// the values are only passed once
+	bb.Out.Reset()
+	r.NoError(echo.Main(ctx, "/root", []string{"again"}))
+	r.Equal("> /root again\n", bb.Out.String())
+}
@@ -0,0 +1,232 @@
+package extplug
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"path"
+	"slices"
+	"sync"
+
+	"github.com/markbates/plugins"
+	"github.com/markbates/plugins/plugcmd"
+)
+
+var _ plugcmd.Aliaser = &proxy{}
+var _ plugcmd.Describer = &proxy{}
+var _ plugcmd.Namer = &proxy{}
+var _ plugins.AvailabilityChecker = &proxy{}
+var _ plugins.IOSetable = &proxy{}
+
+var _ plugcmd.Commander = &commandProxy{}
+var _ plugcmd.Flagger = &flaggerProxy{}
+var _ plugcmd.Commander = &flaggerCommandProxy{}
+var _ plugcmd.Flagger = &flaggerCommandProxy{}
+
+// Interfaceser is implemented by every plugin returned
+// by a Client and reports the interfaces the child
+// advertised for it.
+type Interfaceser interface {
+	plugins.Plugin
+	Interfaces() []string
+}
+
+// newProxy returns a host side plugin for the info.
+// Commander and Flagger change how plugcmd treats a
+// plugin, so they are only implemented when advertised.
+// Namer, Describer, Aliaser, and AvailabilityChecker are
+// always implemented, falling back to the same behavior
+// plugcmd uses when they are missing.
+func newProxy(c *Client, info PluginInfo) plugins.Plugin {
+	p := &proxy{
+		c:    c,
+		info: info,
+	}
+
+	cmd := p.has(IfaceCommander)
+	fl := p.has(IfaceFlagger)
+
+	switch {
+	case cmd && fl:
+		return &flaggerCommandProxy{proxy: p}
+	case cmd:
+		return &commandProxy{proxy: p}
+	case fl:
+		return &flaggerProxy{proxy: p}
+	}
+
+	return p
+}
+
+type proxy struct {
+	c    *Client
+	info PluginInfo
+
+	mu  sync.Mutex
+	set *flag.FlagSet // the last set returned by Flags
+}
+
+func (p *proxy) has(iface string) bool {
+	return slices.Contains(p.info.Interfaces, iface)
+}
+
+func (p *proxy) PluginName() string {
+	return p.info.Name
+}
+
+func (p *proxy) Interfaces() []string {
+	return slices.Clone(p.info.Interfaces)
+}
+
+func (p *proxy) CmdName() string {
+	if p.info.CmdName != "" {
+		return p.info.CmdName
+	}
+	return path.Base(p.info.Name)
+}
+
+func (p *proxy) Description() string {
+	return p.info.Description
+}
+
+func (p *proxy) CmdAliases() []string {
+	return slices.Clone(p.info.Aliases)
+}
+
+func (p *proxy) PluginAvailable(root string) bool {
+	if !p.has(IfaceAvailabilityChecker) {
+		return true
+	}
+
+	ctx, cancel := withTimeout(context.Background())
+	defer cancel()
+
+	var ok bool
+	params := availableParams{Plugin: p.info.Name, Root: root}
+	if err := p.c.call(ctx, methodAvailable, params, &ok); err != nil {
+		slog.Error("failed to check plugin availability",
+			"plugin", p.info.Name,
+			"error", err)
+		return false
+	}
+
+	return ok
+}
+
+// SetStdio sets the IO that output from the child
+// is forwarded to. The IO is shared by every plugin
+// served by the same child.
+func (p *proxy) SetStdio(oi plugins.IO) error {
+	return p.c.SetStdio(oi)
+}
+
+// main calls Main in the child. The flags set on the
+// host, on the set last returned by Flags, that are not
+// in the args, such as those parsed before Main is called
+// with only the positional args, are passed to the child
+// ahead of the args, as `-name=value`.
+func (p *proxy) main(ctx context.Context, root string, args []string) error {
+	params := mainParams{Plugin: p.info.Name, Root: root, Args: p.withFlags(args)}
+	return p.c.call(ctx, methodMain, params, nil)
+}
+
+func (p *proxy) withFlags(args []string) []string {
+	p.mu.Lock()
+	set := p.set
+	p.set = nil
+	p.mu.Unlock()
+
+	if set == nil {
+		return args
+	}
+
+	// the flags that are in the args
+	given := map[string]bool{}
+	in := p.newSet()
+	in.SetOutput(io.Discard)
+	in.Parse(args)
+	in.Visit(func(f *flag.Flag) {
+		given[f.Name] = true
+	})
+
+	var res []string
+	set.Visit(func(f *flag.Flag) {
+		if !given[f.Name] {
+			res = append(res, fmt.Sprintf("-%s=%s", f.Name, f.Value))
+		}
+	})
+
+	return append(res, args...)
+}
+
+func (p *proxy) flags() (*flag.FlagSet, error) {
+	set := p.newSet()
+
+	p.mu.Lock()
+	p.set = set
+	p.mu.Unlock()
+
+	return set, nil
+}
+
+func (p *proxy) newSet() *flag.FlagSet {
+	set := flag.NewFlagSet(p.CmdName(), flag.ContinueOnError)
+	for _, fi := range p.info.Flags {
+		set.Var(&remoteValue{value: fi.Default, bool: fi.Bool}, fi.Name, fi.Usage)
+	}
+	return set
+}
+
+type commandProxy struct {
+	*proxy
+}
+
+func (p *commandProxy) Main(ctx context.Context, root string, args []string) error {
+	return p.main(ctx, root, args)
+}
+
+type flaggerProxy struct {
+	*proxy
+}
+
+func (p *flaggerProxy) Flags() (*flag.FlagSet, error) {
+	return p.flags()
+}
+
+type flaggerCommandProxy struct {
+	*proxy
+}
+
+func (p *flaggerCommandProxy) Main(ctx context.Context, root string, args []string) error {
+	return p.main(ctx, root, args)
+}
+
+func (p *flaggerCommandProxy) Flags() (*flag.FlagSet, error) {
+	return p.flags()
+}
+
+// remoteValue is a flag.Value standing in for a flag
+// defined in the child. Flags are parsed by the child
+// from the args passed to Main, see proxy.main.
+type remoteValue struct {
+	value string
+	bool  bool
+}
+
+func (v *remoteValue) String() string {
+	if v == nil {
+		return ""
+	}
+	return v.value
+}
+
+func (v *remoteValue) Set(s string) error {
+	v.value = s
+	return nil
+}
+
+func (v *remoteValue) IsBoolFlag() bool {
+	return v.bool
+}
@@ -0,0 +1,339 @@
+package extplug
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/markbates/iox"
+	"github.com/markbates/plugins"
+	"github.com/markbates/plugins/plugcmd"
+)
+
+// maxLine is the largest protocol message accepted.
+const maxLine = 16 << 20
+
+// Serve exposes the plugins over the process's stdin
+// and stdout until stdin is closed or the context is
+// done. While serving, os.Stdout is pointed at os.Stderr
+// so stray writes do not corrupt the protocol.
+func Serve(ctx context.Context, plugs plugins.Plugins) error {
+	out := os.Stdout
+	os.Stdout = os.Stderr
+	defer func() {
+		os.Stdout = out
+	}()
+
+	return ServeConn(ctx, plugs, os.Stdin, out)
+}
+
+// ServeConn exposes the plugins over the given reader
+// and writer until the reader returns io.EOF or the
+// context is done. Plugins that implement IOSetable
+// have their output forwarded to the host.
+func ServeConn(ctx context.Context, plugs plugins.Plugins, r io.Reader, w io.Writer) error {
+	if err := plugs.Validate(); err != nil {
+		return err
+	}
+
+	srv := &server{
+		plugs: plugs,
+		w:     w,
+		calls: map[int64]context.CancelFunc{},
+	}
+
+	// in-flight calls are canceled, then waited on
+	defer srv.wg.Wait()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	oi := iox.IO{
+		In:  bytes.NewReader(nil),
+		Out: srv.output(methodStdout),
+		Err: srv.output(methodStderr),
+	}
+
+	if err := plugs.SetStdio(oi); err != nil {
+		return err
+	}
+
+	lines := make(chan []byte)
+	errc := make(chan error, 1)
+
+	go func() {
+		scan := bufio.NewScanner(r)
+		scan.Buffer(make([]byte, 0, 64*1024), maxLine)
+		for scan.Scan() {
+			line := bytes.Clone(scan.Bytes())
+			select {
+			case lines <- line:
+			case <-ctx.Done():
+				return
+			}
+		}
+		errc <- scan.Err()
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case err := <-errc:
+			return err
+		case line := <-lines:
+			srv.dispatch(ctx, line)
+		}
+	}
+}
+
+type server struct {
+	plugs plugins.Plugins
+	w     io.Writer
+	calls map[int64]context.CancelFunc
+
+	wg  sync.WaitGroup
+	wmu sync.Mutex
+	cmu sync.Mutex
+}
+
+func (srv *server) dispatch(ctx context.Context, line []byte) {
+	if len(bytes.TrimSpace(line)) == 0 {
+		return
+	}
+
+	var msg message
+	if err := json.Unmarshal(line, &msg); err != nil {
+		srv.send(message{
+			Error: &Error{Code: CodeParseError, Message: err.Error()},
+		})
+		return
+	}
+
+	if msg.Method == methodCancel {
+		var cp cancelParams
+		if err := json.Unmarshal(msg.Params, &cp); err != nil {
+			return
+		}
+		srv.cmu.Lock()
+		if fn, ok := srv.calls[cp.ID]; ok {
+			fn()
+		}
+		srv.cmu.Unlock()
+		return
+	}
+
+	if msg.ID == nil {
+		return
+	}
+
+	id := *msg.ID
+
+	ctx, cancel := context.WithCancel(ctx)
+	srv.cmu.Lock()
+	srv.calls[id] = cancel
+	srv.cmu.Unlock()
+
+	srv.wg.Add(1)
+	go func() {
+		defer srv.wg.Done()
+		defer func() {
+			srv.cmu.Lock()
+			delete(srv.calls, id)
+			srv.cmu.Unlock()
+			cancel()
+		}()
+
+		res, err := srv.handle(ctx, msg)
+		srv.reply(id, res, err)
+	}()
+}
+
+func (srv *server) handle(ctx context.Context, msg message) (any, error) {
+	switch msg.Method {
+	case methodHandshake:
+		return srv.handshake()
+	case methodAvailable:
+		var ap availableParams
+		if err := json.Unmarshal(msg.Params, &ap); err != nil {
+			return nil, &Error{Code: CodeInvalidParams, Message: err.Error()}
+		}
+
+		p, err := srv.find(ap.Plugin)
+		if err != nil {
+			return nil, err
+		}
+
+		ac, ok := p.(plugins.AvailabilityChecker)
+		if !ok {
+			return true, nil
+		}
+		return ac.PluginAvailable(ap.Root), nil
+	case methodMain:
+		var mp mainParams
+		if err := json.Unmarshal(msg.Params, &mp); err != nil {
+			return nil, &Error{Code: CodeInvalidParams, Message: err.Error()}
+		}
+
+		p, err := srv.find(mp.Plugin)
+		if err != nil {
+			return nil, err
+		}
+
+		c, ok := p.(plugcmd.Commander)
+		if !ok {
+			return nil, &Error{Code: CodeInvalidParams, Message: fmt.Sprintf("plugin %s is not a Commander", mp.Plugin)}
+		}
+
+		return nil, c.Main(ctx, mp.Root, mp.Args)
+	}
+
+	return nil, &Error{Code: CodeMethodNotFound, Message: fmt.Sprintf("method not found: %s", msg.Method)}
+}
+
+func (srv *server) find(name string) (plugins.Plugin, error) {
+	for _, p := range srv.plugs {
+		if p.PluginName() == name {
+			return p, nil
+		}
+	}
+	return nil, &Error{Code: CodeInvalidParams, Message: fmt.Sprintf("unknown plugin: %s", name)}
+}
+
+func (srv *server) handshake() (Handshake, error) {
+	hs := Handshake{
+		Version: Version,
+		Plugins: make([]PluginInfo, 0, len(srv.plugs)),
+	}
+
+	for _, p := range srv.plugs {
+		info, err := describe(p)
+		if err != nil {
+			return hs, err
+		}
+		hs.Plugins = append(hs.Plugins, info)
+	}
+
+	return hs, nil
+}
+
+func describe(p plugins.Plugin) (PluginInfo, error) {
+	info := PluginInfo{
+		Name: p.PluginName(),
+	}
+
+	if _, ok := p.(plugcmd.Commander); ok {
+		info.Interfaces = append(info.Interfaces, IfaceCommander)
+	}
+
+	if n, ok := p.(plugcmd.Namer); ok {
+		info.Interfaces = append(info.Interfaces, IfaceNamer)
+		info.CmdName = n.CmdName()
+	}
+
+	if d, ok := p.(plugcmd.Describer); ok {
+		info.Interfaces = append(info.Interfaces, IfaceDescriber)
+		info.Description = d.Description()
+	}
+
+	if a, ok := p.(plugcmd.Aliaser); ok {
+		info.Interfaces = append(info.Interfaces, IfaceAliaser)
+		info.Aliases = a.CmdAliases()
+	}
+
+	if f, ok := p.(plugcmd.Flagger); ok {
+		info.Interfaces = append(info.Interfaces, IfaceFlagger)
+
+		set, err := f.Flags()
+		if err != nil {
+			return info, err
+		}
+
+		set.VisitAll(func(fl *flag.Flag) {
+			fi := FlagInfo{
+				Name:    fl.Name,
+				Usage:   fl.Usage,
+				Default: fl.DefValue,
+			}
+			if bf, ok := fl.Value.(interface{ IsBoolFlag() bool }); ok {
+				fi.Bool = bf.IsBoolFlag()
+			}
+			info.Flags = append(info.Flags, fi)
+		})
+	}
+
+	if _, ok := p.(plugins.AvailabilityChecker); ok {
+		info.Interfaces = append(info.Interfaces, IfaceAvailabilityChecker)
+	}
+
+	return info, nil
+}
+
+func (srv *server) reply(id int64, res any, err error) {
+	msg := message{
+		ID: &id,
+	}
+
+	if err != nil {
+		var rerr *Error
+		if !errors.As(err, &rerr) {
+			rerr = &Error{Code: CodePluginError, Message: err.Error()}
+		}
+		msg.Error = rerr
+		srv.send(msg)
+		return
+	}
+
+	b, err := json.Marshal(res)
+	if err != nil {
+		msg.Error = &Error{Code: CodePluginError, Message: err.Error()}
+		srv.send(msg)
+		return
+	}
+
+	msg.Result = b
+	srv.send(msg)
+}
+
+func (srv *server) send(msg message) error {
+	msg.JSONRPC = "2.0"
+
+	b, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	srv.wmu.Lock()
+	defer srv.wmu.Unlock()
+
+	_, err = srv.w.Write(append(b, '\n'))
+	return err
+}
+
+func (srv *server) output(method string) io.Writer {
+	return writerFn(func(b []byte) (int, error) {
+		params, err := json.Marshal(outputParams{Data: b})
+		if err != nil {
+			return 0, err
+		}
+
+		if err := srv.send(message{Method: method, Params: params}); err != nil {
+			return 0, err
+		}
+
+		return len(b), nil
+	})
+}
+
+type writerFn func(b []byte) (int, error)
+
+func (fn writerFn) Write(b []byte) (int, error) {
+	return fn(b)
+}
@@ -0,0 +1,101 @@
+// Command helper serves a small set of plugins over
+// extplug for the package tests.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/markbates/plugins"
+	"github.com/markbates/plugins/extplug"
+)
+
+type echo struct {
+	oi plugins.IO
+}
+
+func (e *echo) PluginName() string {
+	return "helper/echo"
+}
+
+func (e *echo) Description() string {
+	return "echoes its arguments"
+}
+
+func (e *echo) CmdAliases() []string {
+	return []string{"e"}
+}
+
+func (e *echo) SetStdio(oi plugins.IO) error {
+	e.oi = oi
+	return nil
+}
+
+func (e *echo) Flags() (*flag.FlagSet, error) {
+	set := flag.NewFlagSet("echo", flag.ContinueOnError)
+	set.Bool("upper", false, "print in upper case")
+	set.String("prefix", ">", "prefix for the output")
+	return set, nil
+}
+
+func (e *echo) Main(ctx context.Context, root string, args []string) error {
+	set, err := e.Flags()
+	if err != nil {
+		return err
+	}
+	set.SetOutput(e.oi.Stderr())
+
+	if err := set.Parse(args); err != nil {
+		return err
+	}
+
+	s := strings.Join(set.Args(), " ")
+	if set.Lookup("upper").Value.String() == "true" {
+		s = strings.ToUpper(s)
+	}
+
+	if s == "fail" {
+		return fmt.Errorf("asked to fail")
+	}
+
+	fmt.Fprintf(e.oi.Stdout(), "%s %s %s\n", set.Lookup("prefix").Value, root, s)
+	fmt.Fprintln(e.oi.Stderr(), "done")
+	return nil
+}
+
+type wait struct{}
+
+func (wait) PluginName() string {
+	return "wait"
+}
+
+func (wait) Main(ctx context.Context, root string, args []string) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+type avail struct{}
+
+func (avail) PluginName() string {
+	return "avail"
+}
+
+func (avail) PluginAvailable(root string) bool {
+	return root == "/ok"
+}
+
+func main() {
+	plugs := plugins.Plugins{
+		&echo{},
+		wait{},
+		avail{},
+	}
+
+	if err := extplug.Serve(context.Background(), plugs); err != nil {
+		log.Fatal(err)
+	}
+
+}
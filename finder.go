@@ -44,12 +44,13 @@ func Background(name string) Findable {
 }
 
 // ByType finder can be used to find plugins
-// by their type.
+// by their type. See As for plugins that
+// are an Unwrapper.
 func ByType[T any](plugs Plugins) []T {
 	var res []T
 
 	for _, p := range plugs {
-		if plug, ok := p.(T); ok {
+		if plug, ok := As[T](p); ok {
 			res = append(res, plug)
 		}
 	}
@@ -57,3 +58,23 @@ func ByType[T any](plugs Plugins) []T {
 	return res
 
 }
+
+// As returns the plugin as a T. If it is not a T, but
+// it is an Unwrapper, the plugin it wraps is tried
+// instead, and so on.
+func As[T any](p Plugin) (T, bool) {
+	for p != nil {
+		if t, ok := p.(T); ok {
+			return t, true
+		}
+
+		u, ok := p.(Unwrapper)
+		if !ok {
+			break
+		}
+		p = u.UnwrapPlugin()
+	}
+
+	var t T
+	return t, false
+}
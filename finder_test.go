@@ -72,3 +72,32 @@ func Test_ByType(t *testing.T) {
 	r.Equal(3, int(ints[2]))
 
 }
+
+type unwrapper struct {
+	Plugin
+}
+
+func (u unwrapper) UnwrapPlugin() Plugin {
+	return u.Plugin
+}
+
+func Test_As(t *testing.T) {
+	t.Parallel()
+	r := require.New(t)
+
+	io := &plugtest.IO{}
+	p := unwrapper{unwrapper{io}}
+
+	act, ok := As[IOSetable](p)
+	r.True(ok)
+	r.Equal(io, act)
+
+	_, ok = As[FSSetable](p)
+	r.False(ok)
+
+	_, ok = As[IOSetable](nil)
+	r.False(ok)
+
+	plugs := Plugins{p, plugtest.StringPlugin("a")}
+	r.Len(ByType[IOSetable](plugs), 1)
+}
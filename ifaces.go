@@ -12,6 +12,16 @@ type Scoper interface {
 	ScopedPlugins() Plugins
 }
 
+// Unwrapper is implemented by plugins that decorate
+// another plugin, such as the Commanders returned by
+// plugcmd.Wrap. ByType, As, and the methods of Plugins
+// look through it for the interfaces it doesn't
+// implement itself.
+type Unwrapper interface {
+	Plugin
+	UnwrapPlugin() Plugin
+}
+
 // FeederFn is a function that is used to feed plugins
 // into a Needer implementation.
 type FeederFn func() Plugins
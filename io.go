@@ -44,7 +44,8 @@ type Stdouter interface {
 
 // Stdout returns a io.MultiWriter containing all
 // plugins that implement Outer. If none are found,
-// then os.Stdout is returned
+// then os.Stdout is returned. If only one is found, its
+// writer is returned as is.
 func Stdout(plugs ...Plugin) io.Writer {
 	if len(plugs) == 0 {
 		return os.Stdout
@@ -61,8 +62,13 @@ func Stdout(plugs ...Plugin) io.Writer {
 		return w == nil
 	})
 
-	if len(writers) == 0 {
+	switch len(writers) {
+	case 0:
 		return os.Stdout
+	case 1:
+		// return the writer as is, so callers can
+		// still check if it is a terminal
+		return writers[0]
 	}
 
 	return io.MultiWriter(writers...)
@@ -70,7 +76,8 @@ func Stdout(plugs ...Plugin) io.Writer {
 
 // Stderr returns a io.MultiWriter containing all
 // plugins that implement Outer. If none are found,
-// then os.Stderr is returned
+// then os.Stderr is returned. If only one is found, its
+// writer is returned as is.
 func Stderr(plugs ...Plugin) io.Writer {
 	if len(plugs) == 0 {
 		return os.Stderr
@@ -87,8 +94,13 @@ func Stderr(plugs ...Plugin) io.Writer {
 		return w == nil
 	})
 
-	if len(writers) == 0 {
+	switch len(writers) {
+	case 0:
 		return os.Stderr
+	case 1:
+		// return the writer as is, so callers can
+		// still check if it is a terminal
+		return writers[0]
 	}
 
 	return io.MultiWriter(writers...)
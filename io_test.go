@@ -35,6 +35,10 @@ func Test_Stdout(t *testing.T) {
 	r.Equal("hi", bf1.Out.String())
 	r.Equal("hi", bf2.Out.String())
 	r.Equal("hi", bf3.Out.String())
+
+	// a single writer is returned as is
+	w = Stdout(&plugtest.IO{IO: bf1.IO()})
+	r.Equal(bf1.IO().Stdout(), w)
 }
 
 func Test_Stderr(t *testing.T) {
@@ -62,4 +66,8 @@ func Test_Stderr(t *testing.T) {
 	r.Equal("hi", bf1.Err.String())
 	r.Equal("hi", bf2.Err.String())
 	r.Equal("hi", bf3.Err.String())
+
+	// a single writer is returned as is
+	w = Stderr(&plugtest.IO{IO: bf1.IO()})
+	r.Equal(bf1.IO().Stderr(), w)
 }
@@ -0,0 +1,129 @@
+package plugins
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+)
+
+var _ Starter = Plugins{}
+var _ Stopper = Plugins{}
+
+// Initializer can be implemented by plugins that need to
+// prepare resources before they are started.
+type Initializer interface {
+	Plugin
+	Init(ctx context.Context) error
+}
+
+// Starter can be implemented by plugins that own long
+// running resources, such as servers or caches.
+type Starter interface {
+	Plugin
+	Start(ctx context.Context) error
+}
+
+// Stopper can be implemented by plugins that need to
+// release resources when the application shuts down.
+type Stopper interface {
+	Plugin
+	Stop(ctx context.Context) error
+}
+
+// Start runs Init, followed by Start, for each plugin
+// in dependency order. If any plugin fails, the plugins
+// that were already started are stopped, in reverse
+// order, before the error is returned.
+func (plugs Plugins) Start(ctx context.Context) error {
+	if ctx == nil {
+		return fmt.Errorf("no context.Context provided")
+	}
+
+	sorted, err := plugs.Sorted()
+	if err != nil {
+		return err
+	}
+
+	started := make(Plugins, 0, len(sorted))
+
+	for _, p := range sorted {
+		if err := startPlugin(ctx, p); err != nil {
+			rerr := started.Stop(context.WithoutCancel(ctx))
+			return errors.Join(err, rerr)
+		}
+		started = append(started, p)
+	}
+
+	return nil
+}
+
+func startPlugin(ctx context.Context, p Plugin) error {
+	if err := ctx.Err(); err != nil {
+		return NewPluginError(OpStart, p, err)
+	}
+
+	if i, ok := As[Initializer](p); ok {
+		if err := i.Init(ctx); err != nil {
+			return NewPluginError(OpInit, p, err)
+		}
+	}
+
+	if s, ok := As[Starter](p); ok {
+		if err := s.Start(ctx); err != nil {
+			return NewPluginError(OpStart, p, err)
+		}
+	}
+
+	return nil
+}
+
+// Stop calls Stop on every Stopper in reverse dependency
+// order. Every plugin is given the chance to stop, and all
+// errors are returned joined together. If the context is
+// done before a plugin finishes stopping, the context's
+// error is recorded for that plugin and shutdown moves on.
+// If the dependencies cannot be sorted, plugins are stopped
+// in reverse collection order and the sort error is included.
+func (plugs Plugins) Stop(ctx context.Context) error {
+	if ctx == nil {
+		return fmt.Errorf("no context.Context provided")
+	}
+
+	var errs []error
+
+	sorted, err := plugs.Sorted()
+	if err != nil {
+		errs = append(errs, err)
+		sorted = plugs
+	}
+
+	stoppers := ByType[Stopper](sorted)
+	slices.Reverse(stoppers)
+
+	for _, s := range stoppers {
+		if err := stopPlugin(ctx, s); err != nil {
+			errs = append(errs, NewPluginError(OpStop, s, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func stopPlugin(ctx context.Context, s Stopper) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	ch := make(chan error, 1)
+	go func() {
+		ch <- s.Stop(ctx)
+	}()
+
+	select {
+	case err := <-ch:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,179 @@
+package plugins_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/markbates/plugins"
+	"github.com/markbates/plugins/plugtest"
+	"github.com/stretchr/testify/require"
+)
+
+type lifecycleLog struct {
+	events []string
+
+	mu sync.Mutex
+}
+
+func (l *lifecycleLog) add(s string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.events = append(l.events, s)
+}
+
+type lifecyclePlugin struct {
+	name     string
+	log      *lifecycleLog
+	initErr  error
+	startErr error
+	stopErr  error
+	stopWait time.Duration
+}
+
+func (p *lifecyclePlugin) PluginName() string {
+	return p.name
+}
+
+func (p *lifecyclePlugin) Init(ctx context.Context) error {
+	p.log.add("init " + p.name)
+	return p.initErr
+}
+
+func (p *lifecyclePlugin) Start(ctx context.Context) error {
+	p.log.add("start " + p.name)
+	return p.startErr
+}
+
+func (p *lifecyclePlugin) Stop(ctx context.Context) error {
+	if p.stopWait > 0 {
+		time.Sleep(p.stopWait)
+	}
+	p.log.add("stop " + p.name)
+	return p.stopErr
+}
+
+func Test_Plugins_Start(t *testing.T) {
+	t.Parallel()
+	r := require.New(t)
+
+	log := &lifecycleLog{}
+	plugs := Plugins{
+		&lifecyclePlugin{name: "a", log: log},
+		plugtest.Simple(1),
+		&lifecyclePlugin{name: "b", log: log},
+	}
+
+	ctx := context.Background()
+	r.NoError(plugs.Start(ctx))
+	r.NoError(plugs.Stop(ctx))
+
+	exp := []string{
+		"init a", "start a",
+		"init b", "start b",
+		"stop b", "stop a",
+	}
+	r.Equal(exp, log.events)
+}
+
+func Test_Plugins_Start_Rollback(t *testing.T) {
+	t.Parallel()
+	r := require.New(t)
+
+	log := &lifecycleLog{}
+	boom := errors.New("boom")
+	plugs := Plugins{
+		&lifecyclePlugin{name: "a", log: log},
+		&lifecyclePlugin{name: "b", log: log},
+		&lifecyclePlugin{name: "c", log: log, startErr: boom},
+		&lifecyclePlugin{name: "d", log: log},
+	}
+
+	err := plugs.Start(context.Background())
+	r.Error(err)
+	r.ErrorIs(err, boom)
+	r.Contains(err.Error(), "failed to start plugin c")
+
+	exp := []string{
+		"init a", "start a",
+		"init b", "start b",
+		"init c", "start c",
+		"stop b", "stop a",
+	}
+	r.Equal(exp, log.events)
+}
+
+func Test_Plugins_Start_InitError(t *testing.T) {
+	t.Parallel()
+	r := require.New(t)
+
+	log := &lifecycleLog{}
+	boom := errors.New("boom")
+	plugs := Plugins{
+		&lifecyclePlugin{name: "a", log: log, initErr: boom},
+	}
+
+	err := plugs.Start(context.Background())
+	r.ErrorIs(err, boom)
+	r.Contains(err.Error(), "failed to init plugin a")
+	r.Equal([]string{"init a"}, log.events)
+}
+
+func Test_Plugins_Start_Canceled(t *testing.T) {
+	t.Parallel()
+	r := require.New(t)
+
+	log := &lifecycleLog{}
+	plugs := Plugins{
+		&lifecyclePlugin{name: "a", log: log},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := plugs.Start(ctx)
+	r.ErrorIs(err, context.Canceled)
+	r.Empty(log.events)
+}
+
+func Test_Plugins_Stop_AggregatesErrors(t *testing.T) {
+	t.Parallel()
+	r := require.New(t)
+
+	log := &lifecycleLog{}
+	e1 := errors.New("e1")
+	e2 := errors.New("e2")
+	plugs := Plugins{
+		&lifecyclePlugin{name: "a", log: log, stopErr: e1},
+		&lifecyclePlugin{name: "b", log: log},
+		&lifecyclePlugin{name: "c", log: log, stopErr: e2},
+	}
+
+	err := plugs.Stop(context.Background())
+	r.ErrorIs(err, e1)
+	r.ErrorIs(err, e2)
+	r.Contains(err.Error(), "failed to stop plugin a")
+	r.Contains(err.Error(), "failed to stop plugin c")
+	r.Equal([]string{"stop c", "stop b", "stop a"}, log.events)
+}
+
+func Test_Plugins_Stop_Deadline(t *testing.T) {
+	t.Parallel()
+	r := require.New(t)
+
+	log := &lifecycleLog{}
+	plugs := Plugins{
+		&lifecyclePlugin{name: "a", log: log},
+		&lifecyclePlugin{name: "b", log: log, stopWait: time.Second},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	err := plugs.Stop(ctx)
+	r.ErrorIs(err, context.DeadlineExceeded)
+	r.Contains(err.Error(), "failed to stop plugin b")
+	r.Contains(err.Error(), "failed to stop plugin a")
+}
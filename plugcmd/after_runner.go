@@ -0,0 +1,31 @@
+package plugcmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/markbates/plugins"
+)
+
+// AfterRunner can be implemented by any plugin, in the
+// collection given to Run, or scoped by a command being
+// run, to be called after the resolved command's Main.
+// It is always called, even if a BeforeRunner, or Main,
+// failed, and err is the error from that failure.
+type AfterRunner interface {
+	plugins.Plugin
+	AfterRun(ctx context.Context, root string, cmd Commander, args []string, err error) error
+}
+
+var _ AfterRunner = AfterRunnerFn(nil)
+
+// AfterRunnerFn is a function that can be used to implement the AfterRunner interface
+type AfterRunnerFn func(ctx context.Context, root string, cmd Commander, args []string, err error) error
+
+func (fn AfterRunnerFn) AfterRun(ctx context.Context, root string, cmd Commander, args []string, err error) error {
+	return fn(ctx, root, cmd, args, err)
+}
+
+func (fn AfterRunnerFn) PluginName() string {
+	return fmt.Sprintf("%T", fn)
+}
@@ -0,0 +1,27 @@
+package plugcmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_AfterRunnerFn(t *testing.T) {
+	t.Parallel()
+	r := require.New(t)
+
+	exp := errors.New("boom")
+	fn := AfterRunnerFn(func(ctx context.Context, root string, cmd Commander, args []string, err error) error {
+		r.Equal("root", root)
+		r.Equal([]string{"a"}, args)
+		return err
+	})
+
+	err := fn.AfterRun(context.Background(), "root", &runCmd{name: "x"}, []string{"a"}, exp)
+	r.Equal(exp, err)
+
+	r.Equal(fmt.Sprintf("%T", fn), fn.PluginName())
+}
@@ -0,0 +1,150 @@
+package plugcmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/markbates/plugins"
+)
+
+// ArgsSpec can be implemented by a Commander to declare
+// its positional arguments. Run validates the arguments
+// against the spec before calling Main, and Print renders
+// a usage synopsis from it.
+//
+//	func (c *Copy) ArgsSpec() []plugcmd.Arg {
+//		return []plugcmd.Arg{
+//			plugcmd.Required("src"),
+//			plugcmd.Variadic("dst", 0, plugcmd.Unlimited),
+//		}
+//	}
+//
+//	Usage: app copy <src> [dst...]
+type ArgsSpec interface {
+	plugins.Plugin
+	ArgsSpec() []Arg
+}
+
+var _ ArgsSpec = ArgsSpecFn(nil)
+
+// ArgsSpecFn is a function that can be used to implement the ArgsSpec interface
+type ArgsSpecFn func() []Arg
+
+func (fn ArgsSpecFn) ArgsSpec() []Arg {
+	return fn()
+}
+
+func (fn ArgsSpecFn) PluginName() string {
+	return fmt.Sprintf("%T", fn)
+}
+
+// Unlimited is the Max of an Arg that can be
+// given any number of times.
+const Unlimited = -1
+
+// Arg is a named positional argument. Min and Max are
+// the number of values it takes, and each value must pass
+// every validator.
+type Arg struct {
+	Name       string
+	Min        int // values required
+	Max        int // values allowed, or Unlimited
+	Validators []func(value string) error
+}
+
+// Required returns an Arg that must be given once.
+func Required(name string, validators ...func(string) error) Arg {
+	return Arg{Name: name, Min: 1, Max: 1, Validators: validators}
+}
+
+// Optional returns an Arg that can be given once.
+func Optional(name string, validators ...func(string) error) Arg {
+	return Arg{Name: name, Min: 0, Max: 1, Validators: validators}
+}
+
+// Variadic returns an Arg that takes between min and max
+// values. Use Unlimited for no max.
+func Variadic(name string, min int, max int, validators ...func(string) error) Arg {
+	return Arg{Name: name, Min: min, Max: max, Validators: validators}
+}
+
+// String returns the arg as it appears in a usage
+// synopsis, such as `<src>`, `[dst]`, or `[dst...]`.
+func (a Arg) String() string {
+	name := a.Name
+	if a.Max != 1 {
+		name += "..."
+	}
+
+	if a.Min == 0 {
+		return "[" + name + "]"
+	}
+	return "<" + name + ">"
+}
+
+// ValidateArgs checks the args against the specs. Values
+// are given to each Arg in order, each taking as many
+// as it can, while leaving enough for the Args after it.
+func ValidateArgs(specs []Arg, args []string) error {
+	// report the first Arg that can't
+	// get the values it requires
+	left := len(args)
+	for _, a := range specs {
+		if left < a.Min {
+			return fmt.Errorf("missing argument %s", a)
+		}
+		left -= a.Min
+	}
+
+	rest := args
+
+	for i, a := range specs {
+		var after int
+		for _, b := range specs[i+1:] {
+			after += b.Min
+		}
+
+		n := len(rest) - after
+		if a.Max != Unlimited {
+			n = min(n, a.Max)
+		}
+
+		for _, v := range rest[:n] {
+			for _, fn := range a.Validators {
+				if err := fn(v); err != nil {
+					return fmt.Errorf("invalid argument %s %q: %w", a, v, err)
+				}
+			}
+		}
+
+		rest = rest[n:]
+	}
+
+	if len(rest) > 0 {
+		return fmt.Errorf("too many arguments: %s", strings.Join(rest, " "))
+	}
+
+	return nil
+}
+
+// synopsis returns the usage synopsis for the command
+// named by names, if it has an ArgsSpec.
+//
+//	app copy [flags] <src> [dst...]
+func synopsis(names []string, p plugins.Plugin) string {
+	as, ok := plugins.As[ArgsSpec](p)
+	if !ok {
+		return ""
+	}
+
+	parts := append([]string{}, names...)
+	if _, ok := p.(Flagger); ok {
+		parts = append(parts, "[flags]")
+	}
+
+	for _, a := range as.ArgsSpec() {
+		parts = append(parts, a.String())
+	}
+
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,194 @@
+package plugcmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/markbates/iox"
+	"github.com/markbates/plugins"
+	"github.com/markbates/plugins/plugtest"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ArgsSpecFn(t *testing.T) {
+	t.Parallel()
+	r := require.New(t)
+
+	exp := []Arg{Required("src")}
+	fn := ArgsSpecFn(func() []Arg {
+		return exp
+	})
+
+	r.Equal(exp, fn.ArgsSpec())
+	r.Equal(fmt.Sprintf("%T", fn), fn.PluginName())
+}
+
+func Test_Arg_String(t *testing.T) {
+	t.Parallel()
+	r := require.New(t)
+
+	r.Equal("<src>", Required("src").String())
+	r.Equal("[dst]", Optional("dst").String())
+	r.Equal("[dst...]", Variadic("dst", 0, Unlimited).String())
+	r.Equal("<dst...>", Variadic("dst", 1, 3).String())
+}
+
+func Test_ValidateArgs(t *testing.T) {
+	t.Parallel()
+
+	noDash := func(s string) error {
+		if strings.HasPrefix(s, "-") {
+			return errors.New("must not start with a dash")
+		}
+		return nil
+	}
+
+	copySpec := []Arg{
+		Required("src", noDash),
+		Variadic("dst", 0, Unlimited),
+	}
+
+	pairSpec := []Arg{
+		Variadic("in", 1, 2),
+		Required("out"),
+	}
+
+	table := []struct {
+		name  string
+		specs []Arg
+		args  []string
+		err   string
+	}{
+		{"none", nil, nil, ""},
+		{"too many", nil, []string{"a"}, "too many arguments: a"},
+		{"exact", copySpec, []string{"a"}, ""},
+		{"variadic", copySpec, []string{"a", "b", "c", "d"}, ""},
+		{"missing", copySpec, nil, "missing argument <src>"},
+		{"invalid", copySpec, []string{"-a", "b"}, `invalid argument <src> "-a": must not start with a dash`},
+		{"leaves for later", pairSpec, []string{"a", "b"}, ""},
+		{"max", pairSpec, []string{"a", "b", "c"}, ""},
+		{"over max", pairSpec, []string{"a", "b", "c", "d"}, "too many arguments: d"},
+		{"under min", pairSpec, []string{"a"}, "missing argument <out>"},
+		{"optional", []Arg{Optional("a"), Optional("b")}, []string{"x"}, ""},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			r := require.New(t)
+
+			err := ValidateArgs(tt.specs, tt.args)
+			if len(tt.err) == 0 {
+				r.NoError(err)
+				return
+			}
+			r.Error(err)
+			r.Equal(tt.err, err.Error())
+		})
+	}
+}
+
+type argsCmd struct {
+	*runCmd
+	specs []Arg
+}
+
+func (c argsCmd) ArgsSpec() []Arg {
+	return c.specs
+}
+
+func Test_ArgsSpec_Print(t *testing.T) {
+	t.Parallel()
+	r := require.New(t)
+
+	c := argsCmd{
+		runCmd: &runCmd{name: "copy"},
+		specs: []Arg{
+			Required("src"),
+			Variadic("dst", 0, Unlimited),
+		},
+	}
+
+	m, err := Describe(c)
+	r.NoError(err)
+	r.Equal("copy <src> [dst...]", m.Synopsis)
+	r.Equal([]HelpArg{
+		{Name: "src", Min: 1, Max: 1},
+		{Name: "dst", Min: 0, Max: Unlimited},
+	}, m.Args)
+
+	bb := &bytes.Buffer{}
+	r.NoError(PrintWith(bb, c, PrintOptions{Path: []string{"app"}}))
+
+	exp := `$ copy
+------
+github.com/markbates/plugins/plugcmd.argsCmd
+
+Usage: app copy <src> [dst...]
+`
+	r.Equal(exp, bb.String())
+
+	bb.Reset()
+	r.NoError(PrintWith(bb, c, PrintOptions{HideSynopsis: true}))
+	r.NotContains(bb.String(), "Usage:")
+
+	dir := t.TempDir()
+	r.NoError(GenManTree(c, dir))
+
+	b, err := os.ReadFile(filepath.Join(dir, "copy.1"))
+	r.NoError(err)
+	r.Contains(string(b), ".SH SYNOPSIS\n.B copy <src> [dst...]\n")
+}
+
+func Test_ArgsSpec_Run(t *testing.T) {
+	t.Parallel()
+	r := require.New(t)
+
+	var called []string
+	c := argsCmd{
+		runCmd: &runCmd{
+			name: "copy",
+			fn: func(args []string) error {
+				called = args
+				return nil
+			},
+		},
+		specs: []Arg{
+			Required("src"),
+			Optional("dst"),
+		},
+	}
+
+	bb := &iox.Buffer{}
+	plugs := plugins.Plugins{
+		runSubCmd{&runCmd{name: "files", subs: []Commander{c}}},
+		&plugtest.IO{IO: bb.IO()},
+	}
+
+	ctx := context.Background()
+
+	err := Run(ctx, "", []string{"files", "copy"}, plugs)
+	r.ErrorIs(err, ErrUsage)
+	r.Equal("files copy: usage error: missing argument <src>", err.Error())
+	r.Nil(called)
+
+	app := filepath.Base(os.Args[0])
+	r.Contains(bb.Err.String(), "Usage: "+app+" files copy <src> [dst]\n")
+
+	err = Run(ctx, "", []string{"files", "copy", "a", "b", "c"}, plugs)
+	r.ErrorIs(err, ErrUsage)
+	r.Contains(err.Error(), "too many arguments: c")
+
+	r.NoError(Run(ctx, "", []string{"files", "copy", "a", "b"}, plugs))
+	r.Equal([]string{"a", "b"}, called)
+
+	bb.Out.Reset()
+	r.NoError(Run(ctx, "", []string{"files", "copy", "-h"}, plugs))
+	r.Contains(bb.Out.String(), "Usage: "+app+" files copy <src> [dst]\n")
+}
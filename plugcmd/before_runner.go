@@ -0,0 +1,30 @@
+package plugcmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/markbates/plugins"
+)
+
+// BeforeRunner can be implemented by any plugin, in the
+// collection given to Run, or scoped by a command being
+// run, to be called before the resolved command's Main.
+// Returning an error stops the command from running.
+type BeforeRunner interface {
+	plugins.Plugin
+	BeforeRun(ctx context.Context, root string, cmd Commander, args []string) error
+}
+
+var _ BeforeRunner = BeforeRunnerFn(nil)
+
+// BeforeRunnerFn is a function that can be used to implement the BeforeRunner interface
+type BeforeRunnerFn func(ctx context.Context, root string, cmd Commander, args []string) error
+
+func (fn BeforeRunnerFn) BeforeRun(ctx context.Context, root string, cmd Commander, args []string) error {
+	return fn(ctx, root, cmd, args)
+}
+
+func (fn BeforeRunnerFn) PluginName() string {
+	return fmt.Sprintf("%T", fn)
+}
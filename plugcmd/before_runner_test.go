@@ -0,0 +1,27 @@
+package plugcmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_BeforeRunnerFn(t *testing.T) {
+	t.Parallel()
+	r := require.New(t)
+
+	exp := errors.New("boom")
+	fn := BeforeRunnerFn(func(ctx context.Context, root string, cmd Commander, args []string) error {
+		r.Equal("root", root)
+		r.Equal([]string{"a"}, args)
+		return exp
+	})
+
+	err := fn.BeforeRun(context.Background(), "root", &runCmd{name: "x"}, []string{"a"})
+	r.Equal(exp, err)
+
+	r.Equal(fmt.Sprintf("%T", fn), fn.PluginName())
+}
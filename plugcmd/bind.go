@@ -0,0 +1,120 @@
+package plugcmd
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// Source is where the value of a flag came from.
+type Source string
+
+// Sources of flag values, from highest to
+// lowest precedence.
+const (
+	SourceArgs    Source = "args"
+	SourceEnv     Source = "env"
+	SourceConfig  Source = "config"
+	SourceDefault Source = "default"
+)
+
+var nonEnv = regexp.MustCompile(`[^A-Z0-9]+`)
+
+// EnvName returns the environment variable for the flag
+// of the command at path. Namespaced flags, from Clean,
+// are supported.
+//
+//	EnvName([]string{"app", "build"}, "vet-mode") // APP_BUILD_VET_MODE
+func EnvName(path []string, name string) string {
+	parts := append(append([]string{}, path...), name)
+	s := strings.ToUpper(strings.Join(parts, "_"))
+	return strings.Trim(nonEnv.ReplaceAllString(s, "_"), "_")
+}
+
+// ConfigKey returns the key, in a Config, for the flag of
+// the command at path. The first element of path is the
+// name of the application, and is not part of the key.
+//
+//	ConfigKey([]string{"app", "build"}, "tags") // build.tags
+func ConfigKey(path []string, name string) string {
+	if len(path) < 2 {
+		return name
+	}
+	return strings.Join(path[1:], ".") + "." + name
+}
+
+// Bind fills in the flags of the set, for the command at
+// path, that were not given as arguments. Values come from
+// the environment, see EnvName, then from cfg, see
+// ConfigKey, which can be nil. Call Bind after the set has
+// been parsed, so the precedence is:
+//
+//	args > env > config > default
+//
+// Bind returns where it found the value of each flag it
+// set, by flag name. Give them, and the set, to PrintWith,
+// with PrintOptions, for the help to report the source of
+// each value. Run binds the flags of every command it
+// parses, and does this for its help.
+func Bind(set *flag.FlagSet, path []string, cfg Config) (map[string]Binding, error) {
+	given := map[string]bool{}
+	set.Visit(func(f *flag.Flag) {
+		given[f.Name] = true
+	})
+
+	bindings := map[string]Binding{}
+
+	var err error
+	set.VisitAll(func(f *flag.Flag) {
+		if err != nil || given[f.Name] {
+			return
+		}
+
+		source := SourceEnv
+		from := EnvName(path, f.Name)
+		value, ok := os.LookupEnv(from)
+
+		if !ok {
+			source = SourceConfig
+			from = ConfigKey(path, f.Name)
+			value, ok = cfg[from]
+		}
+
+		if !ok {
+			return
+		}
+
+		if serr := set.Set(f.Name, value); serr != nil {
+			err = fmt.Errorf("invalid value %q for flag -%s from %s %s: %w", value, f.Name, source, from, serr)
+			return
+		}
+
+		bindings[f.Name] = Binding{Source: source, From: from}
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return bindings, nil
+}
+
+// Binding is where Bind found the value of a flag.
+type Binding struct {
+	Source Source
+	From   string // the env var, or config key
+}
+
+// flagSource returns where the value of the flag
+// came from, and the env var, or config key, if any.
+func flagSource(name string, given bool, bindings map[string]Binding) (Source, string) {
+	if b, ok := bindings[name]; ok {
+		return b.Source, b.From
+	}
+	if given {
+		return SourceArgs, ""
+	}
+	return SourceDefault, ""
+}
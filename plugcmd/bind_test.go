@@ -0,0 +1,208 @@
+package plugcmd
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/markbates/plugins"
+	"github.com/markbates/plugins/plugtest"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_EnvName(t *testing.T) {
+	t.Parallel()
+	r := require.New(t)
+
+	r.Equal("APP_BUILD_TAGS", EnvName([]string{"app", "build"}, "tags"))
+	r.Equal("APP_BUILD_VET_MODE", EnvName([]string{"app", "build"}, "vet-mode"))
+	r.Equal("MY_APP_V", EnvName([]string{"my-app"}, "v"))
+}
+
+func Test_ConfigKey(t *testing.T) {
+	t.Parallel()
+	r := require.New(t)
+
+	r.Equal("tags", ConfigKey([]string{"app"}, "tags"))
+	r.Equal("build.tags", ConfigKey([]string{"app", "build"}, "tags"))
+	r.Equal("build.test.vet-mode", ConfigKey([]string{"app", "build", "test"}, "vet-mode"))
+}
+
+type bindFlags struct {
+	race  bool
+	tags  string
+	count int
+	mode  string
+	out   string
+}
+
+func bindSet(bf *bindFlags) *flag.FlagSet {
+	set := flag.NewFlagSet("build", flag.ContinueOnError)
+	set.BoolVar(&bf.race, "race", false, "enable the race detector")
+	set.StringVar(&bf.tags, "tags", "", "build tags")
+	set.IntVar(&bf.count, "count", 1, "run count times")
+	set.StringVar(&bf.mode, "vet-mode", "slow", "vet mode")
+	set.StringVar(&bf.out, "o", "bin", "output dir")
+	return set
+}
+
+func Test_Bind(t *testing.T) {
+	t.Setenv("BIND_BUILD_TAGS", "env-tags")
+	t.Setenv("BIND_BUILD_COUNT", "5")
+	t.Setenv("BIND_BUILD_VET_MODE", "fast")
+	r := require.New(t)
+
+	bf := &bindFlags{}
+	set := bindSet(bf)
+	r.NoError(set.Parse([]string{"-count", "9"}))
+
+	cfg := Config{
+		"build.tags":  "config-tags",
+		"build.race":  "true",
+		"build.count": "7",
+	}
+
+	path := []string{"bind", "build"}
+	bindings, err := Bind(set, path, cfg)
+	r.NoError(err)
+
+	r.Equal(9, bf.count)         // args
+	r.Equal("env-tags", bf.tags) // env over config
+	r.Equal("fast", bf.mode)     // env
+	r.True(bf.race)              // config
+	r.Equal("bin", bf.out)       // default
+
+	// a new set, as most Flaggers return
+	flagger := FlaggerFn(func() (*flag.FlagSet, error) {
+		return bindSet(&bindFlags{}), nil
+	})
+
+	opts := PrintOptions{Flags: set, Bindings: bindings}
+
+	m, err := describe(flagger, opts)
+	r.NoError(err)
+
+	sources := map[string]string{}
+	for _, f := range m.Flags {
+		sources[f.Name] = string(f.Source) + " " + f.From
+	}
+
+	r.Equal(map[string]string{
+		"count":    "args ",
+		"tags":     "env BIND_BUILD_TAGS",
+		"vet-mode": "env BIND_BUILD_VET_MODE",
+		"race":     "config build.race",
+		"o":        "default ",
+	}, sources)
+
+	bb := &bytes.Buffer{}
+	r.NoError(PrintWith(bb, flagger, opts))
+
+	exp := `
+Flag Sources:
+  Flag       Value     Source
+  ----       -----     ------
+  -race      true      config build.race
+  -tags      env-tags  env BIND_BUILD_TAGS
+  -vet-mode  fast      env BIND_BUILD_VET_MODE
+`
+	r.Contains(bb.String(), exp)
+	r.Contains(bb.String(), "  -tags string\n")
+	r.NotContains(bb.String(), "panic")
+}
+
+func Test_Bind_Invalid(t *testing.T) {
+	t.Setenv("BIND_BUILD_COUNT", "many")
+	r := require.New(t)
+
+	set := bindSet(&bindFlags{})
+	r.NoError(set.Parse(nil))
+
+	_, err := Bind(set, []string{"bind", "build"}, nil)
+	r.Error(err)
+	r.Contains(err.Error(), `invalid value "many" for flag -count from env BIND_BUILD_COUNT`)
+}
+
+func Test_Bind_Run(t *testing.T) {
+	r := require.New(t)
+
+	plugs, bb, _, verbose := runTree(t)
+
+	dir := t.TempDir()
+	cfg := "[a.b]\nv = true\n"
+	r.NoError(os.WriteFile(filepath.Join(dir, "app.toml"), []byte(cfg), 0644))
+
+	plugs = append(plugs, ConfigFilerFn(func() string {
+		return "app.toml"
+	}))
+
+	ctx := context.Background()
+
+	r.NoError(Run(ctx, dir, []string{"a", "b", "c"}, plugs))
+	r.True(*verbose)
+
+	r.NoError(Run(ctx, dir, []string{"a", "b", "-h"}, plugs))
+	r.Contains(bb.Out.String(), "Flag Sources:")
+	r.Contains(bb.Out.String(), "-v    true   config a.b.v")
+
+	// env over config
+	env := EnvName([]string{cmdName(runRoot(plugs)), "a", "b"}, "v")
+	t.Setenv(env, "false")
+
+	bb.Out.Reset()
+	r.NoError(Run(ctx, dir, []string{"a", "b", "-h"}, plugs))
+	r.Contains(bb.Out.String(), "-v    false  env "+env)
+
+	// args over env
+	r.NoError(Run(ctx, dir, []string{"a", "b", "-v", "c"}, plugs))
+	r.True(*verbose)
+
+	t.Setenv(env, "nope")
+	err := Run(ctx, dir, []string{"a", "b", "c"}, plugs)
+	r.ErrorIs(err, ErrUsage)
+	r.Contains(err.Error(), `invalid value "nope" for flag -v from env `+env)
+}
+
+func Test_Bind_Run_Reparse(t *testing.T) {
+	r := require.New(t)
+
+	var tags string
+	var got []string
+	c := runFlagCmd{runSubCmd{&runCmd{
+		name: "build",
+		flags: func() *flag.FlagSet {
+			set := flag.NewFlagSet("build", flag.ContinueOnError)
+			set.StringVar(&tags, "tags", "", "build tags")
+			set.Bool("v", false, "verbose")
+			return set
+		},
+	}}}
+
+	// Main parses its args into a new flag set
+	c.fn = func(args []string) error {
+		set := c.flags()
+		if err := set.Parse(args); err != nil {
+			return err
+		}
+		got = set.Args()
+		return nil
+	}
+
+	plugs := plugins.Plugins{c, &plugtest.IO{}}
+
+	env := EnvName([]string{cmdName(runRoot(plugs)), "build"}, "tags")
+	t.Setenv(env, "fromenv")
+
+	ctx := context.Background()
+	r.NoError(Run(ctx, "", []string{"build", "./..."}, plugs))
+	r.Equal("fromenv", tags)
+	r.Equal([]string{"./..."}, got)
+
+	// args over env
+	r.NoError(Run(ctx, "", []string{"build", "-tags", "dev", "x"}, plugs))
+	r.Equal("dev", tags)
+	r.Equal([]string{"x"}, got)
+}
@@ -0,0 +1,65 @@
+package plugcmd
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/markbates/plugins"
+)
+
+// Builtins returns the built-in commands, Help,
+// Version, and Info, ready to be added to a collection.
+//
+//	plugs = append(plugs, plugcmd.Builtins()...)
+//	plugs.WithPlugins(plugs.PluginFeeder())
+func Builtins() plugins.Plugins {
+	return plugins.Plugins{
+		&Help{},
+		&Version{},
+		&Info{},
+	}
+}
+
+// builtin holds the plugins, and stdio, given
+// to a built-in command.
+type builtin struct {
+	feeder plugins.FeederFn
+	io     plugins.IO
+	ioSet  bool
+}
+
+func (b *builtin) WithPlugins(fn plugins.FeederFn) error {
+	b.feeder = fn
+	return nil
+}
+
+func (b *builtin) SetStdio(oi plugins.IO) error {
+	b.io = oi
+	b.ioSet = true
+	return nil
+}
+
+func (b *builtin) Stdio() plugins.IO {
+	return b.io
+}
+
+func (b *builtin) plugins(name string) (plugins.Plugins, error) {
+	if b.feeder == nil {
+		return nil, fmt.Errorf("no plugins given to %s", name)
+	}
+	return b.feeder(), nil
+}
+
+func (b *builtin) stdout(plugs plugins.Plugins) io.Writer {
+	if b.ioSet {
+		return b.io.Stdout()
+	}
+	return plugins.Stdout(plugs...)
+}
+
+func (b *builtin) stderr(plugs plugins.Plugins) io.Writer {
+	if b.ioSet {
+		return b.io.Stderr()
+	}
+	return plugins.Stderr(plugs...)
+}
@@ -0,0 +1,30 @@
+package plugcmd
+
+import (
+	"fmt"
+
+	"github.com/markbates/plugins"
+)
+
+// Completer can be implemented by a Commander to return
+// candidates during shell completion. flag is the name of
+// the flag whose value is being completed, or empty when
+// completing a positional argument. args are the positional
+// arguments that have already been given.
+type Completer interface {
+	plugins.Plugin
+	Complete(args []string, flag string, toComplete string) []string
+}
+
+var _ Completer = CompleterFn(nil)
+
+// CompleterFn is a function that can be used to implement the Completer interface
+type CompleterFn func(args []string, flag string, toComplete string) []string
+
+func (fn CompleterFn) Complete(args []string, flag string, toComplete string) []string {
+	return fn(args, flag, toComplete)
+}
+
+func (fn CompleterFn) PluginName() string {
+	return fmt.Sprintf("%T", fn)
+}
@@ -0,0 +1,22 @@
+package plugcmd
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_CompleterFn(t *testing.T) {
+	t.Parallel()
+	r := require.New(t)
+
+	fn := CompleterFn(func(args []string, flag string, toComplete string) []string {
+		return []string{flag, toComplete}
+	})
+
+	act := fn.Complete(nil, "name", "val")
+	r.Equal([]string{"name", "val"}, act)
+
+	r.Equal(fmt.Sprintf("%T", fn), fn.PluginName())
+}
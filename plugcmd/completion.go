@@ -0,0 +1,294 @@
+package plugcmd
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/markbates/plugins"
+)
+
+// CompleteCmd is the hidden command, handled by Run, that
+// the shell completion scripts call to get candidates.
+//
+//	$ app __complete build -
+//	-race	enable the race detector
+//	-tags	build tags
+const CompleteCmd = "__complete"
+
+// Shells that completion scripts can be written for.
+const (
+	Bash = "bash"
+	Zsh  = "zsh"
+	Fish = "fish"
+)
+
+// Complete writes the shell completion candidates for args
+// to w, one per line. The last arg is the word being
+// completed, and is empty when starting a new word. Each
+// candidate may be followed by a tab and a description.
+//
+// Candidates come from walking the SubCommander tree,
+// the Namer and Aliaser names of the sub-commands, the
+// Flagger flags of the command, including the namespaced
+// flags of its scoped plugins, and any Completer. The
+// descriptions of commands in a group, see Grouper, start
+// with the group's title, such as `[Build]`.
+func Complete(w io.Writer, args []string, plugs plugins.Plugins) error {
+	return printCompletions(w, completions(args, runRoot(plugs)))
+}
+
+func printCompletions(w io.Writer, cands []candidate) error {
+	for _, c := range cands {
+		if len(c.desc) == 0 {
+			fmt.Fprintln(w, c.value)
+			continue
+		}
+		fmt.Fprintf(w, "%s\t%s\n", c.value, oneLine(c.desc))
+	}
+	return nil
+}
+
+type candidate struct {
+	value string
+	desc  string
+}
+
+func completions(args []string, top plugins.Plugin) []candidate {
+	var toComplete string
+	if len(args) > 0 {
+		toComplete = args[len(args)-1]
+		args = args[:len(args)-1]
+	}
+
+	node := top
+	var pos []string
+	var valueFor string
+	var dashdash bool
+
+	for _, a := range args {
+		switch {
+		case len(valueFor) > 0:
+			valueFor = ""
+		case dashdash:
+			pos = append(pos, a)
+		case a == "--":
+			dashdash = true
+		case len(a) > 1 && strings.HasPrefix(a, "-"):
+			name, _, ok := strings.Cut(strings.TrimLeft(a, "-"), "=")
+			if !ok && needsValue(node, name) {
+				valueFor = name
+			}
+		default:
+			if sc, ok := node.(SubCommander); ok && len(pos) == 0 {
+				if c, err := FindE(a, commandPlugins(sc.SubCommands())); err == nil {
+					node = c
+					continue
+				}
+			}
+			pos = append(pos, a)
+		}
+	}
+
+	if len(valueFor) > 0 {
+		return flagValues(node, pos, valueFor, "", toComplete)
+	}
+
+	if !dashdash && strings.HasPrefix(toComplete, "-") {
+		if name, val, ok := strings.Cut(toComplete, "="); ok {
+			return flagValues(node, pos, strings.TrimLeft(name, "-"), name+"=", val)
+		}
+		return flagCandidates(node, toComplete)
+	}
+
+	var res []candidate
+
+	if sc, ok := node.(SubCommander); ok && len(pos) == 0 && !dashdash {
+		for _, c := range visibleCommands(sc.SubCommands()) {
+			d := cmdNote(c)
+			if g := cmdGroup(c); len(g.Title) > 0 {
+				d = strings.TrimSpace(fmt.Sprintf("[%s] %s", g.Title, d))
+			}
+			res = append(res, candidate{value: cmdName(c), desc: d})
+
+			if a, ok := c.(Aliaser); ok {
+				for _, al := range a.CmdAliases() {
+					res = append(res, candidate{value: al, desc: d})
+				}
+			}
+		}
+	}
+
+	if cp, ok := plugins.As[Completer](node); ok {
+		for _, v := range cp.Complete(pos, "", toComplete) {
+			res = append(res, candidate{value: v})
+		}
+	}
+
+	return filterCandidates(res, toComplete)
+}
+
+// completionFlags returns the flags of the plugin's
+// Flagger, along with the namespaced flags of any
+// scoped plugins that are Flaggers.
+func completionFlags(p plugins.Plugin) []*flag.Flag {
+	var flags []*flag.Flag
+
+	if f, ok := p.(Flagger); ok {
+		if set, err := f.Flags(); err == nil {
+			flags = append(flags, SetToSlice(set)...)
+		}
+	}
+
+	if sc, ok := plugins.As[plugins.Scoper](p); ok {
+		for _, f := range plugins.ByType[Flagger](sc.ScopedPlugins()) {
+			if set, err := f.Flags(); err == nil {
+				flags = append(flags, CleanSet(f, set)...)
+			}
+		}
+	}
+
+	return flags
+}
+
+func needsValue(p plugins.Plugin, name string) bool {
+	if r := []rune(name); len(r) == 1 {
+		if long, ok := shorthands(p)[r[0]]; ok {
+			name = long
+		}
+	}
+
+	for _, f := range completionFlags(p) {
+		if f.Name == name {
+			return !isBoolFlag(f)
+		}
+	}
+	return false
+}
+
+func flagCandidates(p plugins.Plugin, toComplete string) []candidate {
+	dash := "-"
+	if strings.HasPrefix(toComplete, "--") {
+		dash = "--"
+	}
+
+	var res []candidate
+	for _, f := range completionFlags(p) {
+		res = append(res, candidate{value: dash + f.Name, desc: f.Usage})
+	}
+
+	return filterCandidates(res, toComplete)
+}
+
+func flagValues(p plugins.Plugin, pos []string, name string, prefix string, toComplete string) []candidate {
+	cp, ok := plugins.As[Completer](p)
+	if !ok {
+		return nil
+	}
+
+	var res []candidate
+	for _, v := range cp.Complete(pos, name, toComplete) {
+		res = append(res, candidate{value: prefix + v})
+	}
+
+	return filterCandidates(res, prefix+toComplete)
+}
+
+func filterCandidates(cands []candidate, prefix string) []candidate {
+	res := make([]candidate, 0, len(cands))
+	seen := map[string]bool{}
+
+	for _, c := range cands {
+		if seen[c.value] || !strings.HasPrefix(c.value, prefix) {
+			continue
+		}
+		seen[c.value] = true
+		res = append(res, c)
+	}
+
+	return res
+}
+
+func oneLine(s string) string {
+	s, _, _ = strings.Cut(s, "\n")
+	return strings.TrimSpace(s)
+}
+
+var nonIdent = regexp.MustCompile(`[^a-zA-Z0-9_]`)
+
+// WriteCompletion writes a completion script for the given
+// shell, Bash, Zsh, or Fish, for the application named app.
+// The script calls `app __complete` to get the candidates,
+// so completions always reflect the current command tree.
+//
+//	$ app completion bash > /etc/bash_completion.d/app
+func WriteCompletion(w io.Writer, shell string, app string) error {
+	var script string
+	switch shell {
+	case Bash:
+		script = bashCompletion
+	case Zsh:
+		script = zshCompletion
+	case Fish:
+		script = fishCompletion
+	default:
+		return fmt.Errorf("unsupported shell: %q", shell)
+	}
+
+	if len(app) == 0 {
+		return fmt.Errorf("no app name provided")
+	}
+
+	rep := strings.NewReplacer(
+		"{{app}}", app,
+		"{{fn}}", nonIdent.ReplaceAllString(app, "_"),
+		"{{complete}}", CompleteCmd,
+	)
+
+	_, err := rep.WriteString(w, script)
+	return err
+}
+
+const bashCompletion = `# bash completion for {{app}}
+_{{fn}}_complete() {
+    local cur="${COMP_WORDS[COMP_CWORD]}"
+    local IFS=$'\n'
+    local out
+    out=$({{app}} {{complete}} "${COMP_WORDS[@]:1:COMP_CWORD}" 2>/dev/null | cut -f1)
+    COMPREPLY=($(compgen -W "${out}" -- "${cur}"))
+}
+complete -o default -F _{{fn}}_complete {{app}}
+`
+
+const zshCompletion = `#compdef {{app}}
+# zsh completion for {{app}}
+_{{fn}}() {
+    local -a completions
+    local line value desc
+    for line in "${(@f)$({{app}} {{complete}} "${(@)words[2,CURRENT]}" 2>/dev/null)}"; do
+        [[ -z "$line" ]] && continue
+        value="${line%%$'\t'*}"
+        value="${value//:/\\:}"
+        if [[ "$line" == *$'\t'* ]]; then
+            desc="${line#*$'\t'}"
+            completions+=("${value}:${desc}")
+        else
+            completions+=("${value}")
+        fi
+    done
+    _describe '{{app}}' completions
+}
+compdef _{{fn}} {{app}}
+`
+
+const fishCompletion = `# fish completion for {{app}}
+function __{{fn}}_complete
+    set -l tokens (commandline -opc)
+    set -e tokens[1]
+    set -l cur (commandline -ct)
+    {{app}} {{complete}} $tokens "$cur" 2>/dev/null
+end
+complete -c {{app}} -f -a '(__{{fn}}_complete)'
+`
@@ -0,0 +1,175 @@
+package plugcmd
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/markbates/plugins"
+	"github.com/stretchr/testify/require"
+)
+
+type completeCmd struct {
+	runFlagCmd
+	plugs plugins.Plugins
+}
+
+func (c completeCmd) ScopedPlugins() plugins.Plugins {
+	return c.plugs
+}
+
+func (c completeCmd) Complete(args []string, flag string, toComplete string) []string {
+	switch flag {
+	case "tags":
+		return []string{"dev", "prod"}
+	case "":
+		return []string{"./...", "./cmd/" + strings.Join(args, "/")}
+	}
+	return nil
+}
+
+type namedFlagger struct {
+	name string
+	set  func() *flag.FlagSet
+}
+
+func (n namedFlagger) PluginName() string {
+	return n.name
+}
+
+func (n namedFlagger) Flags() (*flag.FlagSet, error) {
+	return n.set(), nil
+}
+
+func completeTree() plugins.Plugins {
+	build := completeCmd{
+		runFlagCmd: runFlagCmd{runSubCmd{&runCmd{
+			name: "build",
+			flags: func() *flag.FlagSet {
+				set := flag.NewFlagSet("build", flag.ContinueOnError)
+				set.Bool("race", false, "enable the race detector")
+				set.String("tags", "", "build tags\nspace separated")
+				return set
+			},
+		}}},
+		plugs: plugins.Plugins{
+			namedFlagger{name: "x/vet", set: func() *flag.FlagSet {
+				set := flag.NewFlagSet("", flag.ContinueOnError)
+				set.String("mode", "", "plugin mode")
+				return set
+			}},
+		},
+	}
+
+	db := runSubCmd{&runCmd{
+		name: "db",
+		subs: []Commander{
+			mega{name: "db/migrate", cmdName: "migrate", aliases: []string{"m"}},
+			mega{name: "db/seed", cmdName: "seed"},
+		},
+	}}
+
+	return plugins.Plugins{build, db}
+}
+
+func complete(t *testing.T, args ...string) []string {
+	t.Helper()
+
+	bb := &bytes.Buffer{}
+	require.NoError(t, Complete(bb, args, completeTree()))
+
+	s := strings.TrimSpace(bb.String())
+	if len(s) == 0 {
+		return nil
+	}
+	return strings.Split(s, "\n")
+}
+
+func Test_Complete(t *testing.T) {
+	t.Parallel()
+
+	table := []struct {
+		name string
+		args []string
+		exp  []string
+	}{
+		{"commands", []string{""}, []string{"build", "db"}},
+		{"command prefix", []string{"d"}, []string{"db"}},
+		{"sub-commands", []string{"db", ""}, []string{"migrate", "m", "seed"}},
+		{"sub-command prefix", []string{"db", "s"}, []string{"seed"}},
+		{"flags", []string{"build", "-"}, []string{
+			"-race\tenable the race detector",
+			"-tags\tbuild tags",
+			"-vet-mode\tplugin mode",
+		}},
+		{"double dash flags", []string{"build", "--r"}, []string{"--race\tenable the race detector"}},
+		{"flag value", []string{"build", "-tags", ""}, []string{"dev", "prod"}},
+		{"flag value prefix", []string{"build", "-tags", "p"}, []string{"prod"}},
+		{"flag equals value", []string{"build", "-tags=d"}, []string{"-tags=dev"}},
+		{"bool flag", []string{"build", "-race", ""}, []string{"./...", "./cmd/"}},
+		{"positional", []string{"build", "-tags", "dev", "a", ""}, []string{"./...", "./cmd/a"}},
+		{"unknown", []string{"nope", ""}, nil},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			r := require.New(t)
+			r.Equal(tt.exp, complete(t, tt.args...))
+		})
+	}
+}
+
+func Test_Run_Complete(t *testing.T) {
+	t.Parallel()
+	r := require.New(t)
+
+	plugs, bb, called, _ := runTree(t)
+
+	err := Run(context.Background(), "", []string{CompleteCmd, "a", "b", ""}, plugs)
+	r.NoError(err)
+	r.Equal("c\n", bb.Out.String())
+	r.Empty(*called)
+}
+
+func Test_WriteCompletion(t *testing.T) {
+	t.Parallel()
+
+	for _, sh := range []string{Bash, Zsh, Fish} {
+		t.Run(sh, func(t *testing.T) {
+			r := require.New(t)
+
+			bb := &bytes.Buffer{}
+			r.NoError(WriteCompletion(bb, sh, "my-app"))
+
+			s := bb.String()
+			r.Contains(s, "my-app __complete")
+			r.Contains(s, "_my_app")
+			r.NotContains(s, "{{")
+		})
+	}
+
+	bb := &bytes.Buffer{}
+	require.Error(t, WriteCompletion(bb, "powershell", "app"))
+	require.Error(t, WriteCompletion(bb, Bash, ""))
+}
+
+func Test_WriteCompletion_BashSyntax(t *testing.T) {
+	t.Parallel()
+	r := require.New(t)
+
+	bash, err := exec.LookPath("bash")
+	if err != nil {
+		t.Skip("bash not found")
+	}
+
+	bb := &bytes.Buffer{}
+	r.NoError(WriteCompletion(bb, Bash, "app"))
+
+	c := exec.Command(bash, "-n")
+	c.Stdin = bb
+	out, err := c.CombinedOutput()
+	r.NoError(err, string(out))
+}
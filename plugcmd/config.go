@@ -0,0 +1,213 @@
+package plugcmd
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Config is a flat set of settings, read by LoadConfig.
+// Keys are the command path, without the application name,
+// and the flag name, joined with dots.
+//
+//	tags        -tags of the application
+//	build.tags  -tags of `app build`
+type Config map[string]string
+
+// LoadConfig reads the config file name, relative to root.
+// Files ending in `.json` are read as JSON, nested objects
+// become dotted keys. Everything else is read as INI, or
+// simple TOML.
+//
+//	# comments start with # or ;
+//	tags = "dev"
+//
+//	[build]
+//	tags = prod
+//	race = true
+//	paths = ["./...", "./cmd"]
+//
+// Arrays are joined with commas. A missing file is not an
+// error, and returns an empty Config.
+func LoadConfig(root string, name string) (Config, error) {
+	fp := name
+	if !filepath.IsAbs(fp) {
+		fp = filepath.Join(root, name)
+	}
+
+	b, err := os.ReadFile(fp)
+	if errors.Is(err, os.ErrNotExist) {
+		return Config{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if strings.EqualFold(filepath.Ext(fp), ".json") {
+		return parseJSONConfig(b)
+	}
+
+	return parseINIConfig(name, bytes.NewReader(b))
+}
+
+func parseJSONConfig(b []byte) (Config, error) {
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+
+	var mm map[string]any
+	if err := dec.Decode(&mm); err != nil {
+		return nil, err
+	}
+
+	cfg := Config{}
+	var walk func(prefix string, mm map[string]any)
+	walk = func(prefix string, mm map[string]any) {
+		for k, v := range mm {
+			if len(prefix) > 0 {
+				k = prefix + "." + k
+			}
+
+			if sub, ok := v.(map[string]any); ok {
+				walk(k, sub)
+				continue
+			}
+
+			cfg[k] = jsonString(v)
+		}
+	}
+	walk("", mm)
+
+	return cfg, nil
+}
+
+func jsonString(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case []any:
+		parts := make([]string, 0, len(v))
+		for _, p := range v {
+			parts = append(parts, jsonString(p))
+		}
+		return strings.Join(parts, ",")
+	}
+	return fmt.Sprint(v)
+}
+
+func parseINIConfig(name string, r io.Reader) (Config, error) {
+	cfg := Config{}
+
+	var section string
+	var n int
+
+	scan := bufio.NewScanner(r)
+	for scan.Scan() {
+		n++
+		line := strings.TrimSpace(scan.Text())
+
+		if len(line) == 0 || line[0] == '#' || line[0] == ';' {
+			continue
+		}
+
+		if line[0] == '[' {
+			if !strings.HasSuffix(line, "]") {
+				return nil, fmt.Errorf("%s:%d: unterminated section: %s", name, n, line)
+			}
+			section = strings.TrimSpace(line[1 : len(line)-1])
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		key = strings.TrimSpace(key)
+		if !ok || len(key) == 0 {
+			return nil, fmt.Errorf("%s:%d: expected key = value: %s", name, n, line)
+		}
+
+		value, err := iniValue(strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", name, n, err)
+		}
+
+		if len(section) > 0 {
+			key = section + "." + key
+		}
+		cfg[key] = value
+	}
+
+	if err := scan.Err(); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+// iniValue unquotes a value, and joins arrays
+// with commas.
+func iniValue(s string) (string, error) {
+	if strings.HasPrefix(s, "[") {
+		if !strings.HasSuffix(s, "]") {
+			return "", fmt.Errorf("unterminated array: %s", s)
+		}
+
+		body := strings.TrimSpace(s[1 : len(s)-1])
+		if len(body) == 0 {
+			return "", nil
+		}
+
+		var parts []string
+		for _, p := range strings.Split(body, ",") {
+			v, err := iniValue(strings.TrimSpace(p))
+			if err != nil {
+				return "", err
+			}
+			parts = append(parts, v)
+		}
+		return strings.Join(parts, ","), nil
+	}
+
+	switch {
+	case strings.HasPrefix(s, `"`):
+		q, err := strconv.QuotedPrefix(s)
+		if err != nil {
+			return "", fmt.Errorf("invalid string: %s", s)
+		}
+		if err := trailing(s[len(q):]); err != nil {
+			return "", err
+		}
+		return strconv.Unquote(q)
+	case strings.HasPrefix(s, "'"):
+		end := strings.Index(s[1:], "'")
+		if end < 0 {
+			return "", fmt.Errorf("unterminated string: %s", s)
+		}
+		if err := trailing(s[end+2:]); err != nil {
+			return "", err
+		}
+		return s[1 : end+1], nil
+	}
+
+	// trailing comments on bare values
+	if i := strings.Index(s, " #"); i >= 0 {
+		s = strings.TrimSpace(s[:i])
+	}
+	return s, nil
+}
+
+// trailing checks that only a comment
+// follows a quoted value.
+func trailing(s string) error {
+	s = strings.TrimSpace(s)
+	if len(s) == 0 || s[0] == '#' || s[0] == ';' {
+		return nil
+	}
+	return fmt.Errorf("unexpected text after value: %s", s)
+}
@@ -0,0 +1,44 @@
+package plugcmd
+
+import (
+	"fmt"
+
+	"github.com/markbates/plugins"
+)
+
+// ConfigFiler names the config file, relative to root,
+// that Run binds the flags of the commands from, see Bind
+// and LoadConfig. Without one, Run reads `.<app>.toml`.
+// Return "-" to read no config file.
+type ConfigFiler interface {
+	plugins.Plugin
+	ConfigFile() string
+}
+
+var _ ConfigFiler = ConfigFilerFn(nil)
+
+// ConfigFilerFn is a function that can be used to implement the ConfigFiler interface
+type ConfigFilerFn func() string
+
+func (fn ConfigFilerFn) ConfigFile() string {
+	return fn()
+}
+
+func (fn ConfigFilerFn) PluginName() string {
+	return fmt.Sprintf("%T", fn)
+}
+
+// runConfig loads the config file, relative to root,
+// named by the first ConfigFiler in the collection.
+func runConfig(root string, top plugins.Plugin, plugs plugins.Plugins) (Config, error) {
+	name := "." + cmdName(top) + ".toml"
+	if cfs := plugins.ByType[ConfigFiler](plugs); len(cfs) > 0 {
+		name = cfs[0].ConfigFile()
+	}
+
+	if name == "-" || len(name) == 0 {
+		return nil, nil
+	}
+
+	return LoadConfig(root, name)
+}
@@ -0,0 +1,24 @@
+package plugcmd
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ConfigFilerFn(t *testing.T) {
+	t.Parallel()
+	r := require.New(t)
+
+	exp := "app.json"
+	fn := ConfigFilerFn(func() string {
+		return exp
+	})
+
+	act := fn.ConfigFile()
+
+	r.Equal(exp, act)
+
+	r.Equal(fmt.Sprintf("%T", fn), fn.PluginName())
+}
@@ -0,0 +1,123 @@
+package plugcmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func writeConfig(t *testing.T, name string, body string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(body), 0644))
+	return dir
+}
+
+func Test_LoadConfig_INI(t *testing.T) {
+	t.Parallel()
+	r := require.New(t)
+
+	root := writeConfig(t, ".app.toml", `
+# top level
+verbose = true
+name = "my app" # the name
+
+; sections are commands
+[build]
+tags = dev prod
+race=false
+paths = ["./...", './cmd']
+empty = []
+
+[build.vet]
+mode = 'fast'
+`)
+
+	cfg, err := LoadConfig(root, ".app.toml")
+	r.NoError(err)
+
+	r.Equal(Config{
+		"verbose":        "true",
+		"name":           "my app",
+		"build.tags":     "dev prod",
+		"build.race":     "false",
+		"build.paths":    "./...,./cmd",
+		"build.empty":    "",
+		"build.vet.mode": "fast",
+	}, cfg)
+}
+
+func Test_LoadConfig_INI_Errors(t *testing.T) {
+	t.Parallel()
+
+	table := []struct {
+		body string
+		err  string
+	}{
+		{"[build", ".app.ini:1: unterminated section: [build"},
+		{"\nnope", ".app.ini:2: expected key = value: nope"},
+		{"= x", ".app.ini:1: expected key = value: = x"},
+		{`a = "x`, `.app.ini:1: invalid string: "x`},
+		{`a = 'x`, `.app.ini:1: unterminated string: 'x`},
+		{`a = "x" y`, `.app.ini:1: unexpected text after value: y`},
+		{`a = [x`, `.app.ini:1: unterminated array: [x`},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.body, func(t *testing.T) {
+			t.Parallel()
+			r := require.New(t)
+
+			root := writeConfig(t, ".app.ini", tt.body)
+			_, err := LoadConfig(root, ".app.ini")
+			r.Error(err)
+			r.Equal(tt.err, err.Error())
+		})
+	}
+}
+
+func Test_LoadConfig_JSON(t *testing.T) {
+	t.Parallel()
+	r := require.New(t)
+
+	root := writeConfig(t, "app.json", `{
+	"verbose": true,
+	"build": {
+		"tags": "dev",
+		"count": 3,
+		"ratio": 1.5,
+		"paths": ["./...", "./cmd"],
+		"none": null,
+		"vet": {"mode": "fast"}
+	}
+}`)
+
+	cfg, err := LoadConfig(root, "app.json")
+	r.NoError(err)
+
+	r.Equal(Config{
+		"verbose":        "true",
+		"build.tags":     "dev",
+		"build.count":    "3",
+		"build.ratio":    "1.5",
+		"build.paths":    "./...,./cmd",
+		"build.none":     "",
+		"build.vet.mode": "fast",
+	}, cfg)
+
+	root = writeConfig(t, "app.json", `[1, 2]`)
+	_, err = LoadConfig(root, "app.json")
+	r.Error(err)
+}
+
+func Test_LoadConfig_Missing(t *testing.T) {
+	t.Parallel()
+	r := require.New(t)
+
+	cfg, err := LoadConfig(t.TempDir(), ".app.toml")
+	r.NoError(err)
+	r.Empty(cfg)
+}
@@ -0,0 +1,54 @@
+package plugcmd
+
+import (
+	"fmt"
+
+	"github.com/markbates/plugins"
+)
+
+// Deprecator is a command that is being retired. It
+// returns a message, such as why, or when, it will be
+// removed, and the name of the command that replaces it,
+// either of which may be empty.
+//
+// Print lists deprecated commands with a notice, and Run
+// prints a warning to the collection's Stderr before
+// running them.
+type Deprecator interface {
+	plugins.Plugin
+	CmdDeprecated() (message string, replacement string)
+}
+
+var _ Deprecator = DeprecatorFn(nil)
+
+// DeprecatorFn is a function that can be used to implement the Deprecator interface
+type DeprecatorFn func() (string, string)
+
+func (fn DeprecatorFn) CmdDeprecated() (string, string) {
+	return fn()
+}
+
+func (fn DeprecatorFn) PluginName() string {
+	return fmt.Sprintf("%T", fn)
+}
+
+// deprecation returns the deprecation notice for the
+// plugin, such as `deprecated: going away, use "new"`,
+// or "" if it is not a Deprecator.
+func deprecation(p plugins.Plugin) string {
+	d, ok := plugins.As[Deprecator](p)
+	if !ok {
+		return ""
+	}
+
+	msg, repl := d.CmdDeprecated()
+
+	notice := "deprecated"
+	if len(msg) > 0 {
+		notice += ": " + msg
+	}
+	if len(repl) > 0 {
+		notice += fmt.Sprintf(", use %q", repl)
+	}
+	return notice
+}
@@ -0,0 +1,101 @@
+package plugcmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/markbates/iox"
+	"github.com/markbates/plugins"
+	"github.com/markbates/plugins/plugtest"
+	"github.com/stretchr/testify/require"
+)
+
+type deprecatedCmd struct {
+	*runCmd
+	msg  string
+	repl string
+}
+
+func (c deprecatedCmd) Description() string {
+	return "Do the old thing"
+}
+
+func (c deprecatedCmd) CmdDeprecated() (string, string) {
+	return c.msg, c.repl
+}
+
+func Test_DeprecatorFn(t *testing.T) {
+	t.Parallel()
+	r := require.New(t)
+
+	fn := DeprecatorFn(func() (string, string) {
+		return "going away", "new"
+	})
+
+	msg, repl := fn.CmdDeprecated()
+	r.Equal("going away", msg)
+	r.Equal("new", repl)
+
+	r.Equal(fmt.Sprintf("%T", fn), fn.PluginName())
+}
+
+func Test_deprecation(t *testing.T) {
+	t.Parallel()
+
+	table := []struct {
+		name string
+		p    plugins.Plugin
+		exp  string
+	}{
+		{"not deprecated", stringPlug("x"), ""},
+		{"no details", deprecatedCmd{}, "deprecated"},
+		{"message", deprecatedCmd{msg: "going away"}, "deprecated: going away"},
+		{"replacement", deprecatedCmd{repl: "new"}, `deprecated, use "new"`},
+		{"both", deprecatedCmd{msg: "going away", repl: "new"}, `deprecated: going away, use "new"`},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			r := require.New(t)
+			r.Equal(tt.exp, deprecation(tt.p))
+		})
+	}
+}
+
+func Test_Deprecator(t *testing.T) {
+	t.Parallel()
+	r := require.New(t)
+
+	var ran []string
+	old := deprecatedCmd{
+		runCmd: &runCmd{
+			name: "old",
+			fn: func(args []string) error {
+				ran = args
+				return nil
+			},
+		},
+		msg:  "going away",
+		repl: "new",
+	}
+
+	app := runSubCmd{&runCmd{
+		name: "app",
+		subs: []Commander{&runCmd{name: "new"}, old},
+	}}
+
+	bb := &bytes.Buffer{}
+	r.NoError(Print(bb, app))
+	r.Contains(bb.String(), "  Do the old thing (deprecated: going away, use \"new\")\n")
+
+	io := &iox.Buffer{}
+	plugs := plugins.Plugins{app, &plugtest.IO{IO: io.IO()}}
+	r.NoError(Run(context.Background(), "", []string{"app", "old", "x"}, plugs))
+	r.Equal([]string{"x"}, ran)
+
+	name := cmdName(runRoot(plugs))
+	r.Equal(fmt.Sprintf("Warning: %q is deprecated: going away, use \"new\"\n", name+" app old"), io.Err.String())
+	r.Empty(io.Out.String())
+}
@@ -0,0 +1,282 @@
+package plugcmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"io"
+	"slices"
+	"sort"
+
+	"github.com/markbates/plugins"
+)
+
+// HelpModel is everything Print knows about a plugin,
+// gathered by Describe, and decoupled from how it is
+// rendered.
+type HelpModel struct {
+	Name        string        `json:"name"`
+	Type        string        `json:"type"`
+	Description string        `json:"description,omitempty"`
+	Synopsis    string        `json:"synopsis,omitempty"`     // from ArgsSpec
+	Args        []HelpArg     `json:"args,omitempty"`         // from ArgsSpec
+	Usage       string        `json:"usage,omitempty"`        // output of UsagePrinter
+	Aliases     []string      `json:"aliases,omitempty"`      // from Aliaser
+	UserAliases []HelpAlias   `json:"user_aliases,omitempty"` // from UserAliaser
+	FlagUsage   string        `json:"flag_usage,omitempty"`   // flag help, as printed by Print
+	Flags       []HelpFlag    `json:"flags,omitempty"`        // from Flagger
+	Commands    []HelpCommand `json:"commands,omitempty"`     // from SubCommander
+	Plugins     []HelpPlugin  `json:"plugins,omitempty"`      // from plugins.Scoper
+}
+
+// HelpFlag describes a single flag.
+type HelpFlag struct {
+	Name    string `json:"name"`
+	Short   string `json:"short,omitempty"` // from Shorthander
+	Type    string `json:"type"`
+	Default string `json:"default,omitempty"`
+	Usage   string `json:"usage,omitempty"`
+	Value   string `json:"value,omitempty"`
+	Source  Source `json:"source,omitempty"` // see Bind
+	From    string `json:"from,omitempty"`   // env var, or config key
+}
+
+// HelpArg describes a positional argument. A Max
+// of Unlimited means any number of values.
+type HelpArg struct {
+	Name string `json:"name"`
+	Min  int    `json:"min"`
+	Max  int    `json:"max"`
+}
+
+// HelpCommand describes a sub-command.
+type HelpCommand struct {
+	Name         string   `json:"name"`
+	Type         string   `json:"type"`
+	Description  string   `json:"description,omitempty"`
+	Aliases      []string `json:"aliases,omitempty"`
+	Deprecated   string   `json:"deprecated,omitempty"`   // notice, from Deprecator
+	Experimental bool     `json:"experimental,omitempty"` // from Experimental
+	Group        Group    `json:"group,omitzero"`         // from Grouper
+}
+
+// HelpAlias describes a user alias, or macro.
+type HelpAlias struct {
+	Name    string `json:"name"`
+	Command string `json:"command"` // the steps, separated by semicolons
+	From    string `json:"from,omitempty"`
+}
+
+// HelpPlugin describes a scoped plugin.
+type HelpPlugin struct {
+	Name        string `json:"name"`
+	Type        string `json:"type"`
+	Description string `json:"description,omitempty"`
+}
+
+// Describe gathers the help for the plugin, its flags,
+// sub-commands, and scoped plugins, into a HelpModel.
+// Sub-commands are sorted by plugin name and scoped
+// plugins by name, the same as Print. Hidden sub-commands,
+// see Hider, are left out.
+func Describe(main plugins.Plugin) (*HelpModel, error) {
+	return describe(main, PrintOptions{})
+}
+
+// describe is Describe, with the flag set, and its
+// bindings, from the options, if any.
+func describe(main plugins.Plugin, opts PrintOptions) (*HelpModel, error) {
+	m := &HelpModel{
+		Name:        cmdName(main),
+		Type:        typeName(main),
+		Description: desc(main),
+		Aliases:     aliases(main),
+	}
+
+	// the Commanders of the collection
+	// are not a type of the program
+	if _, ok := main.(runRoot); ok {
+		m.Type = ""
+	}
+
+	m.Synopsis = synopsis([]string{m.Name}, main)
+	if as, ok := plugins.As[ArgsSpec](main); ok {
+		for _, a := range as.ArgsSpec() {
+			m.Args = append(m.Args, HelpArg{Name: a.Name, Min: a.Min, Max: a.Max})
+		}
+	}
+
+	if u, ok := plugins.As[UsagePrinter](main); ok {
+		bb := &bytes.Buffer{}
+		if err := u.PrintUsage(bb); err != nil {
+			return nil, err
+		}
+		m.Usage = bb.String()
+	}
+
+	if err := describeFlags(m, main, opts.Flags, opts.Bindings); err != nil {
+		return nil, err
+	}
+
+	if sc, ok := main.(SubCommander); ok {
+		cmds := visibleCommands(sc.SubCommands())
+		sort.Slice(cmds, func(i, j int) bool {
+			return cmds[i].PluginName() < cmds[j].PluginName()
+		})
+
+		for _, c := range cmds {
+			m.Commands = append(m.Commands, HelpCommand{
+				Name:         cmdName(c),
+				Type:         typeName(c),
+				Description:  desc(c),
+				Aliases:      aliases(c),
+				Deprecated:   deprecation(c),
+				Experimental: experimental(c),
+				Group:        cmdGroup(c),
+			})
+		}
+	}
+
+	plugs, err := scopedPlugins(main)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, p := range plugs {
+		m.Plugins = append(m.Plugins, HelpPlugin{
+			Name:        p.PluginName(),
+			Type:        typeName(p),
+			Description: desc(p),
+		})
+	}
+
+	// user aliases are found in the collection Run
+	// resolves from, or in the scoped plugins
+	if r, ok := main.(runRoot); ok {
+		plugs = plugins.Plugins(r)
+	}
+	m.addUserAliases(userAliasList(plugs))
+
+	return m, nil
+}
+
+// addUserAliases adds the aliases that
+// are not in the model already.
+func (m *HelpModel) addUserAliases(aliases []UserAlias) {
+	for _, a := range aliases {
+		dup := slices.ContainsFunc(m.UserAliases, func(h HelpAlias) bool {
+			return h.Name == a.Name
+		})
+		if dup {
+			continue
+		}
+
+		m.UserAliases = append(m.UserAliases, HelpAlias{
+			Name:    a.Name,
+			Command: a.String(),
+			From:    a.From,
+		})
+	}
+}
+
+func describeFlags(m *HelpModel, p plugins.Plugin, flags *flag.FlagSet, bindings map[string]Binding) error {
+	bb := &bytes.Buffer{}
+
+	if u, ok := plugins.As[FlagPrinter](p); ok {
+		bb.WriteString("Flags:\n")
+		if err := u.PrintFlags(bb); err != nil {
+			return err
+		}
+		m.FlagUsage = bb.String()
+	}
+
+	u, ok := p.(Flagger)
+	if !ok {
+		return nil
+	}
+
+	if flags == nil {
+		var err error
+		if flags, err = u.Flags(); err != nil {
+			return err
+		}
+	}
+
+	shorts := shorthands(p)
+
+	letters := map[string]string{}
+	for r, name := range shorts {
+		letters[name] = string(r)
+	}
+
+	given := map[string]bool{}
+	flags.Visit(func(f *flag.Flag) {
+		given[f.Name] = true
+	})
+
+	for _, f := range SetToSlice(flags) {
+		hf := helpFlag(f)
+		hf.Short = letters[f.Name]
+		hf.Source, hf.From = flagSource(f.Name, given[f.Name], bindings)
+		m.Flags = append(m.Flags, hf)
+	}
+
+	if len(m.FlagUsage) > 0 {
+		return nil
+	}
+
+	if len(shorts) > 0 {
+		bb.WriteString("Flags:\n")
+		printGNUDefaults(bb, flags, shorts)
+		m.FlagUsage = bb.String()
+		return nil
+	}
+
+	ow := flags.Output()
+	flags.SetOutput(bb)
+	flags.Usage()
+	flags.SetOutput(ow)
+
+	m.FlagUsage = bb.String()
+	return nil
+}
+
+func helpFlag(f *flag.Flag) HelpFlag {
+	typ, usage := flag.UnquoteUsage(f)
+	if isBoolFlag(f) {
+		typ = "bool"
+	}
+
+	return HelpFlag{
+		Name:    f.Name,
+		Type:    typ,
+		Default: f.DefValue,
+		Usage:   usage,
+		Value:   f.Value.String(),
+	}
+}
+
+// BoundFlags returns the flags whose values
+// came from the environment, or a Config.
+func (m *HelpModel) BoundFlags() []HelpFlag {
+	var flags []HelpFlag
+	for _, f := range m.Flags {
+		if f.Source == SourceEnv || f.Source == SourceConfig {
+			flags = append(flags, f)
+		}
+	}
+	return flags
+}
+
+// PrintJSON writes the HelpModel for the plugin to w as
+// indented JSON.
+func PrintJSON(w io.Writer, main plugins.Plugin) error {
+	m, err := Describe(main)
+	if err != nil {
+		return err
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(m)
+}
@@ -0,0 +1,91 @@
+package plugcmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"testing"
+
+	"github.com/markbates/plugins"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Describe(t *testing.T) {
+	t.Parallel()
+	r := require.New(t)
+
+	c := cmd{
+		name: "main",
+		plugs: plugins.Plugins{
+			stringPlugin("two"),
+			stringPlugin("one"),
+		},
+	}
+
+	m, err := Describe(c)
+	r.NoError(err)
+
+	r.Equal("main", m.Name)
+	r.Equal("github.com/markbates/plugins/plugcmd.cmd", m.Type)
+	r.Equal("Description of main", m.Description)
+	r.Equal("This is how to use main.", m.Usage)
+	r.Equal([]string{"main1", "main2"}, m.Aliases)
+	r.Equal("Flags:\nMy Flags\n", m.FlagUsage)
+	r.Empty(m.Flags)
+
+	r.Len(m.Commands, 3)
+	r.Equal(HelpCommand{
+		Name:        "main sub1",
+		Type:        "github.com/markbates/plugins/plugcmd.cmd",
+		Description: "Description of main sub1",
+		Aliases:     []string{"main sub11", "main sub12"},
+	}, m.Commands[0])
+
+	r.Len(m.Plugins, 2)
+	r.Equal("one", m.Plugins[0].Name)
+	r.Equal("string/plugin", m.Plugins[0].Description)
+	r.Equal("two", m.Plugins[1].Name)
+}
+
+func Test_Describe_Flags(t *testing.T) {
+	t.Parallel()
+	r := require.New(t)
+
+	f := FlaggerFn(func() (*flag.FlagSet, error) {
+		set := flag.NewFlagSet("build", flag.ContinueOnError)
+		set.Bool("race", false, "enable the race detector")
+		set.String("tags", "dev", "a list of build `tags`")
+		return set, nil
+	})
+
+	m, err := Describe(f)
+	r.NoError(err)
+
+	r.Equal([]HelpFlag{
+		{Name: "race", Type: "bool", Default: "false", Usage: "enable the race detector", Value: "false", Source: SourceDefault},
+		{Name: "tags", Type: "tags", Default: "dev", Usage: "a list of build tags", Value: "dev", Source: SourceDefault},
+	}, m.Flags)
+	r.Contains(m.FlagUsage, "Usage of build:")
+	r.Contains(m.FlagUsage, "-tags tags")
+}
+
+func Test_PrintJSON(t *testing.T) {
+	t.Parallel()
+	r := require.New(t)
+
+	c := cmd{name: "main"}
+
+	bb := &bytes.Buffer{}
+	r.NoError(PrintJSON(bb, c))
+
+	exp, err := Describe(c)
+	r.NoError(err)
+
+	act := &HelpModel{}
+	r.NoError(json.Unmarshal(bb.Bytes(), act))
+	r.Equal(exp, act)
+
+	r.Contains(bb.String(), `"name": "main"`)
+	r.Contains(bb.String(), `"flag_usage": "Flags:\nMy Flags\n"`)
+	r.NotContains(bb.String(), `"plugins"`)
+}
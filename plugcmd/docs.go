@@ -0,0 +1,403 @@
+package plugcmd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/markbates/plugins"
+)
+
+// maxDocDepth guards against SubCommander trees that
+// never end, such as commands that return themselves.
+const maxDocDepth = 32
+
+// GenMarkdownTree writes a Markdown page for root, and
+// for every command below it in the SubCommander tree,
+// into dir. Pages are named after the command path, for
+// example `app_build.md`, and the output is deterministic.
+// Hidden commands, see Hider, are left out, and the
+// Available Commands are listed by Grouper group.
+func GenMarkdownTree(root Commander, dir string) error {
+	return genTree(root, dir, ".md", writeMarkdown)
+}
+
+// GenManTree writes a roff man page, in section 1, for
+// root, and for every command below it in the SubCommander
+// tree, into dir. Pages are named after the command path,
+// for example `app-build.1`, and the output is deterministic.
+// Hidden commands, see Hider, are left out, and the
+// COMMANDS are listed by Grouper group.
+func GenManTree(root Commander, dir string) error {
+	return genTree(root, dir, ".1", writeMan)
+}
+
+// docPage is a single command in the tree, along
+// with the commands above it.
+type docPage struct {
+	path []Commander
+}
+
+func (d docPage) cmd() Commander {
+	return d.path[len(d.path)-1]
+}
+
+func (d docPage) names() []string {
+	names := make([]string, 0, len(d.path))
+	for _, c := range d.path {
+		names = append(names, cmdName(c))
+	}
+	return names
+}
+
+func (d docPage) title() string {
+	return strings.Join(d.names(), " ")
+}
+
+func (d docPage) parent() (docPage, bool) {
+	if len(d.path) < 2 {
+		return docPage{}, false
+	}
+	return docPage{path: d.path[:len(d.path)-1]}, true
+}
+
+func (d docPage) children() []docPage {
+	var res []docPage
+	for _, c := range sortedSubCommands(d.cmd()) {
+		path := append(append([]Commander{}, d.path...), c)
+		res = append(res, docPage{path: path})
+	}
+	return res
+}
+
+// groupPages sorts the pages into the
+// groups of their commands, see Grouper.
+func groupPages(pages []docPage) []itemGroup[docPage] {
+	return groupItems(pages, func(d docPage) Group {
+		return cmdGroup(d.cmd())
+	})
+}
+
+var unsafeFileChars = regexp.MustCompile(`[^a-zA-Z0-9._-]+`)
+
+func (d docPage) fileName(sep string, ext string) string {
+	names := d.names()
+	for i, n := range names {
+		names[i] = unsafeFileChars.ReplaceAllString(n, "_")
+	}
+	return strings.Join(names, sep) + ext
+}
+
+func sortedSubCommands(p plugins.Plugin) []Commander {
+	sc, ok := p.(SubCommander)
+	if !ok {
+		return nil
+	}
+
+	cmds := visibleCommands(sc.SubCommands())
+	sort.SliceStable(cmds, func(i, j int) bool {
+		return cmdName(cmds[i]) < cmdName(cmds[j])
+	})
+	return cmds
+}
+
+func genTree(root Commander, dir string, ext string, fn func(w io.Writer, d docPage) error) error {
+	if root == nil {
+		return fmt.Errorf("no root Commander provided")
+	}
+
+	// walk the whole tree first so a bad
+	// tree doesn't leave partial output behind
+	var pages []docPage
+
+	var walk func(d docPage) error
+	walk = func(d docPage) error {
+		if len(d.path) > maxDocDepth {
+			return fmt.Errorf("command tree deeper than %d: %s", maxDocDepth, cmdName(d.path[0]))
+		}
+
+		pages = append(pages, d)
+		for _, c := range d.children() {
+			if err := walk(c); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if err := walk(docPage{path: []Commander{root}}); err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	sep := "_"
+	if ext == ".1" {
+		sep = "-"
+	}
+
+	for _, d := range pages {
+		bb := &bytes.Buffer{}
+		if err := fn(bb, d); err != nil {
+			return err
+		}
+
+		fp := filepath.Join(dir, d.fileName(sep, ext))
+		if err := os.WriteFile(fp, bb.Bytes(), 0644); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// usageText returns the output of the plugin's
+// UsagePrinter, if it has one.
+func usageText(p plugins.Plugin) (string, error) {
+	u, ok := plugins.As[UsagePrinter](p)
+	if !ok {
+		return "", nil
+	}
+
+	bb := &bytes.Buffer{}
+	if err := u.PrintUsage(bb); err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(bb.String()), nil
+}
+
+// flagsText returns the output of the plugin's
+// FlagPrinter, or the defaults of its Flagger.
+func flagsText(p plugins.Plugin) (string, error) {
+	bb := &bytes.Buffer{}
+
+	if u, ok := plugins.As[FlagPrinter](p); ok {
+		if err := u.PrintFlags(bb); err != nil {
+			return "", err
+		}
+		return strings.TrimRight(bb.String(), "\n"), nil
+	}
+
+	if u, ok := p.(Flagger); ok {
+		flags, err := u.Flags()
+		if err != nil {
+			return "", err
+		}
+
+		ow := flags.Output()
+		flags.SetOutput(bb)
+		flags.PrintDefaults()
+		flags.SetOutput(ow)
+	}
+
+	return strings.TrimRight(bb.String(), "\n"), nil
+}
+
+func aliases(p plugins.Plugin) []string {
+	if a, ok := p.(Aliaser); ok {
+		return a.CmdAliases()
+	}
+	return nil
+}
+
+func writeMarkdown(w io.Writer, d docPage) error {
+	c := d.cmd()
+
+	fmt.Fprintf(w, "# %s\n", d.title())
+
+	if s := desc(c); len(s) > 0 {
+		fmt.Fprintf(w, "\n%s\n", s)
+	}
+
+	if syn := synopsis(d.names(), c); len(syn) > 0 {
+		fmt.Fprintf(w, "\n## Synopsis\n\n```\n%s\n```\n", syn)
+	}
+
+	usage, err := usageText(c)
+	if err != nil {
+		return err
+	}
+	if len(usage) > 0 {
+		fmt.Fprintf(w, "\n## Usage\n\n```\n%s\n```\n", usage)
+	}
+
+	if al := aliases(c); len(al) > 0 {
+		fmt.Fprintf(w, "\n## Aliases\n\n%s\n", strings.Join(al, ", "))
+	}
+
+	flags, err := flagsText(c)
+	if err != nil {
+		return err
+	}
+	if len(flags) > 0 {
+		fmt.Fprintf(w, "\n## Flags\n\n```\n%s\n```\n", flags)
+	}
+
+	if kids := d.children(); len(kids) > 0 {
+		fmt.Fprint(w, "\n## Available Commands\n")
+		for _, g := range groupPages(kids) {
+			if len(g.Title) > 0 {
+				fmt.Fprintf(w, "\n### %s\n", g.Title)
+			}
+
+			fmt.Fprintln(w)
+			fmt.Fprintln(w, "| Command | Description |")
+			fmt.Fprintln(w, "| ------- | ----------- |")
+			for _, k := range g.items {
+				fmt.Fprintf(w, "| [%s](%s) | %s |\n", k.title(), k.fileName("_", ".md"), mdCell(cmdNote(k.cmd())))
+			}
+		}
+	}
+
+	plugs, err := scopedPlugins(c)
+	if err != nil {
+		return err
+	}
+	if len(plugs) > 0 {
+		fmt.Fprint(w, "\n## Using Plugins\n\n")
+		fmt.Fprintln(w, "| Name | Description | Type |")
+		fmt.Fprintln(w, "| ---- | ----------- | ---- |")
+		for _, p := range plugs {
+			fmt.Fprintf(w, "| %s | %s | `%s` |\n", mdCell(p.PluginName()), mdCell(desc(p)), typeName(p))
+		}
+	}
+
+	if p, ok := d.parent(); ok {
+		fmt.Fprint(w, "\n## See Also\n\n")
+		fmt.Fprintf(w, "* [%s](%s)", p.title(), p.fileName("_", ".md"))
+		if s := desc(p.cmd()); len(s) > 0 {
+			fmt.Fprintf(w, " - %s", s)
+		}
+		fmt.Fprintln(w)
+	}
+
+	return nil
+}
+
+func mdCell(s string) string {
+	s = strings.ReplaceAll(s, "|", `\|`)
+	return strings.ReplaceAll(s, "\n", " ")
+}
+
+func writeMan(w io.Writer, d docPage) error {
+	c := d.cmd()
+
+	fmt.Fprintf(w, ".TH %q \"1\"\n", strings.ToUpper(d.fileName("-", "")))
+
+	fmt.Fprintln(w, ".SH NAME")
+	fmt.Fprint(w, roff(d.fileName("-", "")))
+	if s := desc(c); len(s) > 0 {
+		fmt.Fprintf(w, ` \- %s`, roff(s))
+	}
+	fmt.Fprintln(w)
+
+	fmt.Fprintln(w, ".SH SYNOPSIS")
+	if syn := synopsis(d.names(), c); len(syn) > 0 {
+		fmt.Fprintf(w, ".B %s\n", roff(syn))
+	} else {
+		fmt.Fprintf(w, ".B %s\n", roff(d.title()))
+	}
+
+	usage, err := usageText(c)
+	if err != nil {
+		return err
+	}
+	if len(usage) > 0 {
+		fmt.Fprintln(w, ".SH DESCRIPTION")
+		fmt.Fprintln(w, ".nf")
+		fmt.Fprintln(w, roffLines(usage))
+		fmt.Fprintln(w, ".fi")
+	}
+
+	if al := aliases(c); len(al) > 0 {
+		fmt.Fprintln(w, ".SH ALIASES")
+		fmt.Fprintln(w, roff(strings.Join(al, ", ")))
+	}
+
+	flags, err := flagsText(c)
+	if err != nil {
+		return err
+	}
+	if len(flags) > 0 {
+		fmt.Fprintln(w, ".SH OPTIONS")
+		fmt.Fprintln(w, ".nf")
+		fmt.Fprintln(w, roffLines(flags))
+		fmt.Fprintln(w, ".fi")
+	}
+
+	if kids := d.children(); len(kids) > 0 {
+		fmt.Fprintln(w, ".SH COMMANDS")
+		for _, g := range groupPages(kids) {
+			if len(g.Title) > 0 {
+				fmt.Fprintf(w, ".SS %s\n", roff(g.Title))
+			}
+
+			for _, k := range g.items {
+				fmt.Fprintln(w, ".TP")
+				fmt.Fprintf(w, "\\fB%s\\fR\n", roff(k.title()))
+				fmt.Fprintln(w, roff(cmdNote(k.cmd())))
+			}
+		}
+	}
+
+	plugs, err := scopedPlugins(c)
+	if err != nil {
+		return err
+	}
+	if len(plugs) > 0 {
+		fmt.Fprintln(w, ".SH USING PLUGINS")
+		for _, p := range plugs {
+			fmt.Fprintln(w, ".TP")
+			fmt.Fprintf(w, "\\fB%s\\fR (%s)\n", roff(p.PluginName()), roff(typeName(p)))
+			fmt.Fprintln(w, roff(desc(p)))
+		}
+	}
+
+	var also []string
+	if p, ok := d.parent(); ok {
+		also = append(also, p.fileName("-", ""))
+	}
+	for _, k := range d.children() {
+		also = append(also, k.fileName("-", ""))
+	}
+	if len(also) > 0 {
+		fmt.Fprintln(w, ".SH SEE ALSO")
+		for i, a := range also {
+			sep := ","
+			if i == len(also)-1 {
+				sep = ""
+			}
+			fmt.Fprintf(w, "\\fB%s\\fR(1)%s\n", roff(a), sep)
+		}
+	}
+
+	return nil
+}
+
+// roff escapes s for use in running roff text.
+func roff(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\e`)
+	s = strings.ReplaceAll(s, "-", `\-`)
+	s = strings.ReplaceAll(s, "\n", " ")
+	if strings.HasPrefix(s, ".") || strings.HasPrefix(s, "'") {
+		s = `\&` + s
+	}
+	return s
+}
+
+// roffLines escapes each line of s for use in a
+// no-fill block.
+func roffLines(s string) string {
+	lines := strings.Split(s, "\n")
+	for i, l := range lines {
+		lines[i] = roff(l)
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,186 @@
+package plugcmd
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/markbates/plugins"
+	"github.com/stretchr/testify/require"
+)
+
+type docCmd struct {
+	name    string
+	desc    string
+	usage   string
+	aliases []string
+	flags   []string
+	subs    []Commander
+	plugs   plugins.Plugins
+}
+
+func (c docCmd) PluginName() string {
+	return "docs/" + c.name
+}
+
+func (c docCmd) CmdName() string {
+	return c.name
+}
+
+func (c docCmd) Description() string {
+	return c.desc
+}
+
+func (c docCmd) PrintUsage(w io.Writer) error {
+	_, err := fmt.Fprint(w, c.usage)
+	return err
+}
+
+func (c docCmd) CmdAliases() []string {
+	return c.aliases
+}
+
+func (c docCmd) Flags() (*flag.FlagSet, error) {
+	set := flag.NewFlagSet(c.name, flag.ContinueOnError)
+	for _, f := range c.flags {
+		set.String(f, "", fmt.Sprintf("the %s flag", f))
+	}
+	return set, nil
+}
+
+func (c docCmd) SubCommands() []Commander {
+	return c.subs
+}
+
+func (c docCmd) ScopedPlugins() plugins.Plugins {
+	return c.plugs
+}
+
+func (c docCmd) Main(ctx context.Context, root string, args []string) error {
+	return nil
+}
+
+func docTree() Commander {
+	return docCmd{
+		name:  "app",
+		desc:  "An app",
+		usage: ".starts with a dot\nsecond line",
+		flags: []string{"v"},
+		subs: []Commander{
+			docCmd{
+				name: "test",
+				desc: "Run tests",
+			},
+			docCmd{
+				name:    "build",
+				desc:    "Build | things",
+				aliases: []string{"b"},
+				plugs: plugins.Plugins{
+					stringPlugin("compiler"),
+				},
+			},
+		},
+	}
+}
+
+func readDir(t *testing.T, dir string) map[string]string {
+	t.Helper()
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+
+	res := map[string]string{}
+	for _, e := range entries {
+		b, err := os.ReadFile(filepath.Join(dir, e.Name()))
+		require.NoError(t, err)
+		res[e.Name()] = string(b)
+	}
+	return res
+}
+
+func Test_GenMarkdownTree(t *testing.T) {
+	t.Parallel()
+	r := require.New(t)
+
+	dir := t.TempDir()
+	r.NoError(GenMarkdownTree(docTree(), dir))
+
+	files := readDir(t, dir)
+	r.Len(files, 3)
+
+	exp := "# app\n\nAn app\n\n## Usage\n\n```\n.starts with a dot\nsecond line\n```\n\n## Flags\n\n```\n  -v string\n    \tthe v flag\n```\n\n## Available Commands\n\n| Command | Description |\n| ------- | ----------- |\n| [app build](app_build.md) | Build \\| things |\n| [app test](app_test.md) | Run tests |\n"
+	r.Equal(exp, files["app.md"])
+
+	exp = "# app build\n\nBuild | things\n\n## Aliases\n\nb\n\n## Using Plugins\n\n| Name | Description | Type |\n| ---- | ----------- | ---- |\n| compiler | string/plugin | `github.com/markbates/plugins/plugcmd.stringPlugin` |\n\n## See Also\n\n* [app](app.md) - An app\n"
+	r.Equal(exp, files["app_build.md"])
+
+	// output is deterministic
+	dir2 := t.TempDir()
+	r.NoError(GenMarkdownTree(docTree(), dir2))
+	r.Equal(files, readDir(t, dir2))
+}
+
+func Test_GenManTree(t *testing.T) {
+	t.Parallel()
+	r := require.New(t)
+
+	dir := t.TempDir()
+	r.NoError(GenManTree(docTree(), dir))
+
+	files := readDir(t, dir)
+	r.Len(files, 3)
+
+	exp := `.TH "APP" "1"
+.SH NAME
+app \- An app
+.SH SYNOPSIS
+.B app
+.SH DESCRIPTION
+.nf
+\&.starts with a dot
+second line
+.fi
+.SH OPTIONS
+.nf
+  \-v string
+    	the v flag
+.fi
+.SH COMMANDS
+.TP
+\fBapp build\fR
+Build | things
+.TP
+\fBapp test\fR
+Run tests
+.SH SEE ALSO
+\fBapp\-build\fR(1),
+\fBapp\-test\fR(1)
+`
+	r.Equal(exp, files["app.1"])
+
+	exp = `.TH "APP-TEST" "1"
+.SH NAME
+app\-test \- Run tests
+.SH SYNOPSIS
+.B app test
+.SH SEE ALSO
+\fBapp\fR(1)
+`
+	r.Equal(exp, files["app-test.1"])
+}
+
+func Test_GenTree_TooDeep(t *testing.T) {
+	t.Parallel()
+	r := require.New(t)
+
+	// cmd returns sub-commands forever
+	err := GenMarkdownTree(cmd{name: "main"}, t.TempDir())
+	r.Error(err)
+	r.Contains(err.Error(), "command tree deeper than")
+
+	r.Error(GenManTree(nil, t.TempDir()))
+}
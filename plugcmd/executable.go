@@ -0,0 +1,207 @@
+package plugcmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/markbates/plugins"
+)
+
+var _ Describer = &Executable{}
+var _ NamedCommander = &Executable{}
+var _ plugins.IOSetable = &Executable{}
+
+// RootEnv is the environment variable used to pass
+// the `root` given to Main to an Executable.
+const RootEnv = "PLUGCMD_ROOT"
+
+// Executable is a Commander backed by an executable
+// file, such as `app-build` found on $PATH.
+type Executable struct {
+	// Name is the command name, without the prefix.
+	Name string
+	// Path is the full path to the executable.
+	Path string
+
+	oi plugins.IO
+	mu sync.RWMutex
+}
+
+// PluginName returns the path to the executable.
+func (e *Executable) PluginName() string {
+	return e.Path
+}
+
+// CmdName returns the name of the command, without
+// the prefix.
+func (e *Executable) CmdName() string {
+	return e.Name
+}
+
+// Description reports where the executable was found.
+func (e *Executable) Description() string {
+	return fmt.Sprintf("external command (%s)", e.Path)
+}
+
+// SetStdio sets the IO used for the executable's
+// stdin, stdout, and stderr.
+func (e *Executable) SetStdio(oi plugins.IO) error {
+	if e == nil {
+		return fmt.Errorf("nil Executable")
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.oi = oi
+	return nil
+}
+
+// Main runs the executable with the given args. The
+// executable is run in root, and root is also passed
+// in the RootEnv environment variable.
+func (e *Executable) Main(ctx context.Context, root string, args []string) error {
+	e.mu.RLock()
+	oi := e.oi
+	e.mu.RUnlock()
+
+	c := exec.CommandContext(ctx, e.Path, args...)
+	c.Dir = root
+	c.Env = append(os.Environ(), fmt.Sprintf("%s=%s", RootEnv, root))
+	c.Stdin = oi.Stdin()
+	c.Stdout = oi.Stdout()
+	c.Stderr = oi.Stderr()
+
+	return c.Run()
+}
+
+// ExecFinder returns a finder that adds an Executable for
+// every executable named `<prefix>-<name>` found in dirs.
+// If no dirs are given, $PATH is searched. Like $PATH,
+// the first directory containing a name wins. Executables
+// whose name collides with a Commander already in the
+// collection are skipped, so in-process commands take
+// precedence.
+//
+//	plugs, err = plugs.Find(plugcmd.ExecFinder("app"))
+func ExecFinder(prefix string, dirs ...string) plugins.FinderFn {
+	return func(plugs plugins.Plugins) (plugins.Plugins, error) {
+		search := dirs
+		if len(search) == 0 {
+			search = filepath.SplitList(os.Getenv("PATH"))
+		}
+
+		execs, err := findExecutables(prefix, search)
+		if err != nil {
+			return nil, err
+		}
+
+		res := slices.Clone(plugs)
+		for _, e := range execs {
+			if Find(e.Name, plugs) != nil {
+				continue
+			}
+			res = append(res, e)
+		}
+
+		return res, nil
+	}
+}
+
+func findExecutables(prefix string, dirs []string) ([]*Executable, error) {
+	if len(prefix) == 0 {
+		return nil, fmt.Errorf("no prefix provided")
+	}
+
+	prefix = prefix + "-"
+
+	seen := map[string]bool{}
+	var res []*Executable
+
+	for _, dir := range dirs {
+		if dir == "" {
+			dir = "."
+		}
+
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			// missing or unreadable $PATH entries are skipped
+			continue
+		}
+
+		for _, de := range entries {
+			name, ok := execName(de.Name())
+			if !ok || !strings.HasPrefix(name, prefix) {
+				continue
+			}
+
+			name = strings.TrimPrefix(name, prefix)
+			if len(name) == 0 || seen[name] {
+				continue
+			}
+
+			fp := filepath.Join(dir, de.Name())
+			if !isExecutable(fp) {
+				continue
+			}
+
+			seen[name] = true
+			res = append(res, &Executable{
+				Name: name,
+				Path: fp,
+			})
+		}
+	}
+
+	slices.SortFunc(res, func(a, b *Executable) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	return res, nil
+}
+
+// execName strips the executable extension, on Windows,
+// from the file name.
+func execName(fn string) (string, bool) {
+	if runtime.GOOS != "windows" {
+		return fn, true
+	}
+
+	ext := strings.ToLower(filepath.Ext(fn))
+	if len(ext) == 0 {
+		return "", false
+	}
+
+	exts := os.Getenv("PATHEXT")
+	if len(exts) == 0 {
+		exts = ".com;.exe;.bat;.cmd"
+	}
+
+	for _, e := range filepath.SplitList(strings.ToLower(exts)) {
+		if e == ext {
+			return strings.TrimSuffix(fn, filepath.Ext(fn)), true
+		}
+	}
+
+	return "", false
+}
+
+func isExecutable(fp string) bool {
+	info, err := os.Stat(fp)
+	if err != nil || !info.Mode().IsRegular() {
+		return false
+	}
+
+	if runtime.GOOS == "windows" {
+		return true
+	}
+
+	return info.Mode().Perm()&0111 != 0
+}
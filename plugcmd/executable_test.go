@@ -0,0 +1,98 @@
+package plugcmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/markbates/iox"
+	"github.com/markbates/plugins"
+	"github.com/stretchr/testify/require"
+)
+
+func writeExec(t *testing.T, dir string, name string, body string) string {
+	t.Helper()
+
+	fp := filepath.Join(dir, name)
+	err := os.WriteFile(fp, []byte("#!/bin/sh\n"+body+"\n"), 0755)
+	require.NoError(t, err)
+	return fp
+}
+
+func Test_ExecFinder(t *testing.T) {
+	t.Parallel()
+	if runtime.GOOS == "windows" {
+		t.Skip("uses shell scripts")
+	}
+	r := require.New(t)
+
+	d1 := t.TempDir()
+	d2 := t.TempDir()
+
+	hello := writeExec(t, d1, "app-hello", "echo hello")
+	writeExec(t, d2, "app-hello", "echo shadowed")
+	bye := writeExec(t, d2, "app-bye", "echo bye")
+	writeExec(t, d2, "app-mega", "echo collision")
+	writeExec(t, d2, "other-thing", "echo other")
+
+	err := os.WriteFile(filepath.Join(d1, "app-noexec"), []byte("x"), 0644)
+	r.NoError(err)
+
+	m := mega{aliases: []string{"mega"}}
+	plugs := plugins.Plugins{m}
+
+	res, err := plugs.Find(ExecFinder("app", d1, d2, filepath.Join(d1, "missing")))
+	r.NoError(err)
+	r.Len(res, 3)
+	r.Equal(m, res[0])
+
+	c := Find("hello", res)
+	r.NotNil(c)
+	e, ok := c.(*Executable)
+	r.True(ok)
+	r.Equal(hello, e.Path)
+	r.Equal("hello", e.CmdName())
+	r.Contains(e.Description(), hello)
+
+	c = Find("bye", res)
+	r.NotNil(c)
+	r.Equal(bye, c.PluginName())
+
+	// in-process commands win
+	r.Equal(m, Find("mega", res))
+
+	_, err = plugs.Find(ExecFinder("", d1))
+	r.Error(err)
+}
+
+func Test_Executable_Main(t *testing.T) {
+	t.Parallel()
+	if runtime.GOOS == "windows" {
+		t.Skip("uses shell scripts")
+	}
+	r := require.New(t)
+
+	dir := t.TempDir()
+	writeExec(t, dir, "app-echo", `echo "$@" "$`+RootEnv+`" "$(pwd)"; echo oops >&2; exit 3`)
+
+	res, err := ExecFinder("app", dir)(nil)
+	r.NoError(err)
+	r.Len(res, 1)
+
+	bb := &iox.Buffer{}
+	r.NoError(res.SetStdio(bb.IO()))
+
+	c := Find("echo", res)
+	r.NotNil(c)
+
+	err = c.Main(context.Background(), dir, []string{"a", "b"})
+	r.Error(err)
+
+	wd, err := filepath.EvalSymlinks(dir)
+	r.NoError(err)
+
+	r.Equal("a b "+dir+" "+wd+"\n", bb.Out.String())
+	r.Equal("oops\n", bb.Err.String())
+}
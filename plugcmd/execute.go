@@ -0,0 +1,219 @@
+package plugcmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sync/atomic"
+	"time"
+
+	"github.com/markbates/plugins"
+)
+
+// GracePeriod is how long Execute waits for a command to
+// return after its context is canceled by a signal.
+var GracePeriod = 5 * time.Second
+
+// Execute runs root as the program, with the arguments in
+// os.Args, and returns the exit status. It is meant to be
+// the whole of a main function:
+//
+//	func main() {
+//		os.Exit(plugcmd.Execute(app, plugs))
+//	}
+//
+// The working directory is the root given to the commands,
+// and the process stdio is set on root, and the collection,
+// with SetStdio. Commands are found, and run, like Run, but
+// starting at root, instead of the Commanders in the
+// collection.
+//
+// On SIGINT, or SIGTERM, the context of the command is
+// canceled. If the command doesn't return within the
+// GracePeriod, or a second signal arrives, Execute returns
+// without waiting for it, with 128 plus the signal number.
+// A Shell handles SIGINT itself, and only cancels the
+// command it is running.
+//
+// Errors are printed to Stderr. Usage errors print the help
+// of the command, with Print, and return ExitUsage. Other
+// exit statuses are chosen with ExitCode.
+func Execute(root Commander, plugs plugins.Plugins) int {
+	sigs := make(chan os.Signal, 2)
+	signal.Notify(sigs, shutdownSignals...)
+	defer signal.Stop(sigs)
+
+	oi := plugins.IO{
+		In:  os.Stdin,
+		Out: os.Stdout,
+		Err: os.Stderr,
+	}
+
+	return execute(context.Background(), root, os.Args[1:], plugs, oi, sigs)
+}
+
+func execute(ctx context.Context, root Commander, args []string, plugs plugins.Plugins, oi plugins.IO, sigs <-chan os.Signal) int {
+	stderr := oi.Stderr()
+
+	if root == nil {
+		fmt.Fprintln(stderr, "no root Commander provided")
+		return ExitFailure
+	}
+
+	pwd, err := os.Getwd()
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return ExitFailure
+	}
+
+	all := append(plugins.Plugins{root}, plugs...)
+	for _, p := range plugs {
+		if p != nil && p.PluginName() == root.PluginName() {
+			all = plugs
+			break
+		}
+	}
+
+	if err := all.SetStdio(oi); err != nil {
+		fmt.Fprintln(stderr, err)
+		return ExitFailure
+	}
+
+	all = withStdio(all, oi)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	claims := &interruptClaims{}
+	ctx = context.WithValue(ctx, interruptsKey{}, claims)
+	ctx = context.WithValue(ctx, executedKey{}, executed{root: root, plugs: all})
+
+	done := make(chan error, 1)
+	go func() {
+		done <- run(ctx, pwd, root, args, all)
+	}()
+
+	var sig os.Signal
+	var grace <-chan time.Time
+
+	for {
+		select {
+		case err := <-done:
+			if sig != nil && (err == nil || errors.Is(err, context.Canceled)) {
+				return signalCode(sig)
+			}
+			return exitStatus(stderr, err)
+		case s := <-sigs:
+			if s == os.Interrupt && claims.claimed() {
+				// the command handles SIGINT itself
+				continue
+			}
+
+			if sig != nil {
+				return signalCode(sig)
+			}
+			sig = s
+			cancel()
+			grace = time.After(GracePeriod)
+		case <-grace:
+			return signalCode(sig)
+		}
+	}
+}
+
+// exitStatus prints the error, and returns
+// its exit status.
+func exitStatus(w io.Writer, err error) int {
+	printRunError(w, err)
+	return ExitCode(err)
+}
+
+// printRunError prints an error returned by Run,
+// unless Run already has.
+func printRunError(w io.Writer, err error) {
+	if err == nil {
+		return
+	}
+
+	var nf *NotFoundError
+	var ae *AmbiguousError
+	switch {
+	case errors.As(err, &nf), errors.As(err, &ae):
+	case isSilentExit(err):
+	default:
+		PrintError(w, err)
+	}
+}
+
+// isSilentExit reports if the error is from Exit
+// with a nil error.
+func isSilentExit(err error) bool {
+	var ee *ExitError
+	return errors.As(err, &ee) && ee.Err == nil
+}
+
+// withStdio adds the stdio to the plugins, for Run,
+// unless a plugin already gives Run its writers.
+func withStdio(plugs plugins.Plugins, oi plugins.IO) plugins.Plugins {
+	if len(plugins.ByType[plugins.Stdouter](plugs)) > 0 || len(plugins.ByType[plugins.Stderrer](plugs)) > 0 {
+		return plugs
+	}
+	return append(plugs, stdio{IO: oi})
+}
+
+// stdio gives Run the stdio of Execute, or Shell,
+// when nothing in the collection does.
+type stdio struct {
+	plugins.IO
+}
+
+func (stdio) PluginName() string {
+	return "plugcmd/stdio"
+}
+
+type interruptsKey struct{}
+
+type executedKey struct{}
+
+// executed is the root Commander, and the plugins, that
+// Execute runs commands with, for a Shell to run its
+// commands with, too.
+type executed struct {
+	root  Commander
+	plugs plugins.Plugins
+}
+
+// executedFrom returns what Execute, if
+// it is running, runs commands with.
+func executedFrom(ctx context.Context) (executed, bool) {
+	ex, ok := ctx.Value(executedKey{}).(executed)
+	return ex, ok
+}
+
+// interruptClaims lets a command, such as Shell, handle
+// SIGINT itself while it runs under Execute.
+type interruptClaims struct {
+	n atomic.Int32
+}
+
+func (c *interruptClaims) claimed() bool {
+	return c.n.Load() > 0
+}
+
+// claimInterrupts stops Execute, if it is running the
+// command, from canceling the context on SIGINT, until
+// the returned function is called.
+func claimInterrupts(ctx context.Context) func() {
+	c, ok := ctx.Value(interruptsKey{}).(*interruptClaims)
+	if !ok {
+		return func() {}
+	}
+
+	c.n.Add(1)
+	return func() {
+		c.n.Add(-1)
+	}
+}
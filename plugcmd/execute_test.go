@@ -0,0 +1,152 @@
+package plugcmd
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/markbates/iox"
+	"github.com/markbates/plugins"
+	"github.com/markbates/plugins/plugtest"
+	"github.com/stretchr/testify/require"
+)
+
+func executeTree(fn func(ctx context.Context, args []string) error) Commander {
+	var ctx context.Context
+
+	b := &runCmd{
+		name: "b",
+		fn: func(args []string) error {
+			return fn(ctx, args)
+		},
+	}
+
+	// runCmd doesn't see the context
+	withCtx := func(cmd Commander, next CommanderFn) CommanderFn {
+		return func(c context.Context, root string, args []string) error {
+			ctx = c
+			return next(c, root, args)
+		}
+	}
+
+	return runFlagCmd{runSubCmd{&runCmd{
+		name: "app",
+		subs: []Commander{Wrap(b, withCtx)},
+		flags: func() *flag.FlagSet {
+			return flag.NewFlagSet("app", flag.ContinueOnError)
+		},
+	}}}
+}
+
+func Test_Execute(t *testing.T) {
+	t.Parallel()
+
+	boom := errors.New("boom")
+
+	table := []struct {
+		name string
+		args []string
+		err  error
+		exp  int
+		out  string
+	}{
+		{name: "ok", args: []string{"b"}, exp: ExitOK},
+		{name: "failed", args: []string{"b"}, err: boom, exp: ExitFailure, out: "b: command failed: boom\n"},
+		{name: "exit", args: []string{"b"}, err: Exit(3, boom), exp: 3, out: "b: command failed: boom\n"},
+		{name: "silent", args: []string{"b"}, err: Exit(4, nil), exp: 4},
+		{name: "bad flag", args: []string{"-x"}, exp: ExitUsage, out: "flag provided but not defined: -x"},
+		{name: "main usage", args: []string{"b"}, err: fmt.Errorf("need a thing: %w", ErrUsage), exp: ExitUsage, out: "$ b"},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			r := require.New(t)
+
+			root := executeTree(func(ctx context.Context, args []string) error {
+				return tt.err
+			})
+
+			bb := &iox.Buffer{}
+			code := execute(context.Background(), root, tt.args, nil, bb.IO(), nil)
+			r.Equal(tt.exp, code)
+
+			if len(tt.out) == 0 {
+				r.Empty(bb.Err.String())
+				return
+			}
+			r.Contains(bb.Err.String(), tt.out)
+		})
+	}
+}
+
+func Test_Execute_Stdio(t *testing.T) {
+	t.Parallel()
+	r := require.New(t)
+
+	pwd, err := os.Getwd()
+	r.NoError(err)
+
+	var got string
+	root := CommanderFn(func(ctx context.Context, root string, args []string) error {
+		got = root
+		return nil
+	})
+
+	pio := &plugtest.IO{}
+	bb := &iox.Buffer{}
+
+	code := execute(context.Background(), root, nil, plugins.Plugins{pio}, bb.IO(), nil)
+	r.Equal(ExitOK, code)
+	r.Equal(pwd, got)
+	r.Equal(bb.IO(), pio.IO)
+}
+
+func Test_Execute_Signal(t *testing.T) {
+	t.Parallel()
+	r := require.New(t)
+
+	root := executeTree(func(ctx context.Context, args []string) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	sigs := make(chan os.Signal, 1)
+	sig := shutdownSignals[len(shutdownSignals)-1]
+	sigs <- sig
+
+	bb := &iox.Buffer{}
+	code := execute(context.Background(), root, []string{"b"}, nil, bb.IO(), sigs)
+	r.Equal(signalCode(sig), code)
+	r.Empty(bb.Err.String())
+}
+
+func Test_Execute_Grace(t *testing.T) {
+	r := require.New(t)
+
+	grace := GracePeriod
+	GracePeriod = time.Millisecond
+	t.Cleanup(func() {
+		GracePeriod = grace
+	})
+
+	block := make(chan struct{})
+	t.Cleanup(func() {
+		close(block)
+	})
+
+	root := executeTree(func(ctx context.Context, args []string) error {
+		<-block
+		return nil
+	})
+
+	sigs := make(chan os.Signal, 1)
+	sigs <- os.Interrupt
+
+	bb := &iox.Buffer{}
+	code := execute(context.Background(), root, []string{"b"}, nil, bb.IO(), sigs)
+	r.Equal(signalCode(os.Interrupt), code)
+}
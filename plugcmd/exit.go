@@ -0,0 +1,75 @@
+package plugcmd
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Exit statuses used by ExitCode and Execute.
+const (
+	ExitOK      = 0
+	ExitFailure = 1
+	ExitUsage   = 2
+)
+
+// ExitCoder can be implemented by an error, returned from
+// a Commander's Main, to choose the exit status of the
+// program.
+type ExitCoder interface {
+	error
+	ExitCode() int
+}
+
+var _ ExitCoder = &ExitError{}
+
+// ExitError is an error with an exit status.
+// See Exit.
+type ExitError struct {
+	Code int
+	Err  error
+}
+
+// Exit returns an error that makes Execute exit with the
+// code. If err is nil, nothing is printed on exit.
+//
+//	return plugcmd.Exit(3, fmt.Errorf("tests failed"))
+func Exit(code int, err error) error {
+	return &ExitError{Code: code, Err: err}
+}
+
+func (e *ExitError) Error() string {
+	if e.Err == nil {
+		return fmt.Sprintf("exit status %d", e.Code)
+	}
+	return e.Err.Error()
+}
+
+func (e *ExitError) Unwrap() error {
+	return e.Err
+}
+
+func (e *ExitError) ExitCode() int {
+	return e.Code
+}
+
+// ExitCode returns the exit status for the error. The
+// first ExitCoder in the error's chain decides it, if there
+// is one. Otherwise usage errors, and unknown commands,
+// from Run are ExitUsage, and all other errors are
+// ExitFailure.
+func ExitCode(err error) int {
+	if err == nil {
+		return ExitOK
+	}
+
+	var ec ExitCoder
+	if errors.As(err, &ec) {
+		return ec.ExitCode()
+	}
+
+	if errors.Is(err, ErrUsage) || errors.Is(err, ErrNotFound) {
+		return ExitUsage
+	}
+
+	return ExitFailure
+}
@@ -0,0 +1,49 @@
+package plugcmd
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Exit(t *testing.T) {
+	t.Parallel()
+	r := require.New(t)
+
+	boom := errors.New("boom")
+
+	err := Exit(3, boom)
+	r.Equal("boom", err.Error())
+	r.ErrorIs(err, boom)
+	r.Equal(3, ExitCode(err))
+
+	err = Exit(4, nil)
+	r.Equal("exit status 4", err.Error())
+	r.Equal(4, ExitCode(err))
+}
+
+func Test_ExitCode(t *testing.T) {
+	t.Parallel()
+
+	table := []struct {
+		name string
+		err  error
+		exp  int
+	}{
+		{name: "nil", err: nil, exp: ExitOK},
+		{name: "plain", err: errors.New("boom"), exp: ExitFailure},
+		{name: "usage", err: &RunError{Kind: ErrUsage}, exp: ExitUsage},
+		{name: "not found", err: &RunError{Kind: ErrNotFound}, exp: ExitUsage},
+		{name: "failed", err: &RunError{Kind: ErrFailed, Err: errors.New("boom")}, exp: ExitFailure},
+		{name: "wrapped", err: &RunError{Kind: ErrFailed, Err: fmt.Errorf("x: %w", Exit(5, nil))}, exp: 5},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			r := require.New(t)
+			r.Equal(tt.exp, ExitCode(tt.err))
+		})
+	}
+}
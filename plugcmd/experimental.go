@@ -0,0 +1,33 @@
+package plugcmd
+
+import (
+	"fmt"
+
+	"github.com/markbates/plugins"
+)
+
+// Experimental is a command that may change, or be
+// removed, without notice. Print tags it in the
+// Available Commands.
+type Experimental interface {
+	plugins.Plugin
+	CmdExperimental() bool
+}
+
+var _ Experimental = ExperimentalFn(nil)
+
+// ExperimentalFn is a function that can be used to implement the Experimental interface
+type ExperimentalFn func() bool
+
+func (fn ExperimentalFn) CmdExperimental() bool {
+	return fn()
+}
+
+func (fn ExperimentalFn) PluginName() string {
+	return fmt.Sprintf("%T", fn)
+}
+
+func experimental(p plugins.Plugin) bool {
+	e, ok := plugins.As[Experimental](p)
+	return ok && e.CmdExperimental()
+}
@@ -0,0 +1,61 @@
+package plugcmd
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type experimentalCmd struct {
+	*runCmd
+}
+
+func (experimentalCmd) Description() string {
+	return "Try it out"
+}
+
+func (experimentalCmd) CmdExperimental() bool {
+	return true
+}
+
+func Test_ExperimentalFn(t *testing.T) {
+	t.Parallel()
+	r := require.New(t)
+
+	fn := ExperimentalFn(func() bool {
+		return true
+	})
+
+	r.True(fn.CmdExperimental())
+	r.True(experimental(fn))
+	r.False(experimental(stringPlug("stable")))
+
+	r.Equal(fmt.Sprintf("%T", fn), fn.PluginName())
+}
+
+func Test_Experimental(t *testing.T) {
+	t.Parallel()
+	r := require.New(t)
+
+	app := runSubCmd{&runCmd{
+		name: "app",
+		subs: []Commander{
+			&runCmd{name: "build"},
+			experimentalCmd{&runCmd{name: "preview"}},
+		},
+	}}
+
+	m, err := Describe(app)
+	r.NoError(err)
+	r.Len(m.Commands, 2)
+	r.False(m.Commands[0].Experimental)
+	r.True(m.Commands[1].Experimental)
+
+	bb := &bytes.Buffer{}
+	r.NoError(Print(bb, app))
+	r.Contains(bb.String(), "\n  preview  [experimental] Try it out\n")
+
+	r.Equal("[experimental] Try it out", cmdNote(app.subs[1]))
+}
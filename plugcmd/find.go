@@ -1,7 +1,9 @@
 package plugcmd
 
 import (
+	"fmt"
 	"path"
+	"slices"
 	"strings"
 
 	"github.com/markbates/plugins"
@@ -20,14 +22,63 @@ func FindFromArgs(args []string, plugs plugins.Plugins) Commander {
 	return nil
 }
 
-// Find wraps the other cmd finders into a mega finder for cmds
+// FindArgs is like FindFromArgs, but also returns the args
+// for the command, those after its name. If the name is a
+// user alias, see UserAliaser, the args are those of the
+// expanded alias, followed by the rest of args. Aliases
+// that loop return an *AliasLoopError. A macro runs more
+// than one command, only the first is returned, see
+// ExpandAliases for every step.
+func FindArgs(args []string, plugs plugins.Plugins) (Commander, []string, error) {
+	i := slices.IndexFunc(args, func(a string) bool {
+		return !strings.HasPrefix(a, "-")
+	})
+	if i < 0 {
+		return nil, nil, nil
+	}
+
+	return findArgs(args[i:], plugs)
+}
+
+// findArgs finds the command named by the first of
+// the args, expanding user aliases, and its args.
+func findArgs(args []string, plugs plugins.Plugins) (Commander, []string, error) {
+	steps, err := ExpandAliases(args, plugs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	step := steps[0]
+	if len(step) == 0 {
+		return nil, nil, nil
+	}
+
+	c := findIn(step[0], plugins.ByType[Commander](plugs))
+	if c == nil {
+		return nil, nil, nil
+	}
+
+	return c, step[1:], nil
+}
+
+// Find wraps the other cmd finders into a mega finder for cmds.
+// If no command matches the name, and it is a user alias, see
+// UserAliaser, the alias is expanded, and the command it runs
+// first is returned. Aliases that loop return nil. Use
+// FindArgs for the args the alias gives the command.
 func Find(name string, plugs plugins.Plugins) Commander {
 	if len(plugs) == 0 {
 		return nil
 	}
 
-	cmds := plugins.ByType[Commander](plugs)
+	c, _, err := findArgs([]string{name}, plugs)
+	if err != nil {
+		return nil
+	}
+	return c
+}
 
+func findIn(name string, cmds []Commander) Commander {
 	for _, c := range cmds {
 		if n, ok := c.(Namer); ok {
 			if n.CmdName() == name {
@@ -50,3 +101,103 @@ func Find(name string, plugs plugins.Plugins) Commander {
 
 	return nil
 }
+
+// FindE is like Find, but returns a *NotFoundError when no
+// command matches the name, and an *AmbiguousError when
+// more than one command matches it.
+func FindE(name string, plugs plugins.Plugins) (Commander, error) {
+	cmds := plugins.ByType[Commander](plugs)
+
+	var found []Commander
+	for _, c := range cmds {
+		if matches(name, c) {
+			found = append(found, c)
+		}
+	}
+
+	switch len(found) {
+	case 0:
+		return nil, &NotFoundError{
+			Name:        name,
+			Suggestions: suggest(name, cmds),
+		}
+	case 1:
+		return found[0], nil
+	}
+
+	return nil, &AmbiguousError{
+		Name:       name,
+		Candidates: found,
+	}
+}
+
+// findPath walks the SubCommander tree from top, with
+// FindE, for each of the names.
+func findPath(top plugins.Plugin, names []string) (plugins.Plugin, error) {
+	node := top
+	for _, name := range names {
+		sc, ok := node.(SubCommander)
+		if !ok {
+			return nil, &NotFoundError{Name: name}
+		}
+
+		c, err := FindE(name, commandPlugins(sc.SubCommands()))
+		if err != nil {
+			return nil, err
+		}
+		node = c
+	}
+	return node, nil
+}
+
+func matches(name string, c Commander) bool {
+	if n, ok := c.(Namer); ok {
+		if n.CmdName() == name {
+			return true
+		}
+	}
+
+	if n, ok := c.(Aliaser); ok {
+		if slices.Contains(n.CmdAliases(), name) {
+			return true
+		}
+	}
+
+	return name == path.Base(c.PluginName())
+}
+
+// NotFoundError is returned by FindE when no command
+// matches the name. Suggestions holds the names of
+// similar commands, closest first.
+type NotFoundError struct {
+	Name        string
+	Suggestions []string
+}
+
+func (e *NotFoundError) Error() string {
+	msg := fmt.Sprintf("unknown command %q", e.Name)
+	if len(e.Suggestions) == 0 {
+		return msg
+	}
+	return fmt.Sprintf("%s, did you mean %q?", msg, e.Suggestions[0])
+}
+
+// Is reports whether the target is ErrNotFound.
+func (e *NotFoundError) Is(target error) bool {
+	return target == ErrNotFound
+}
+
+// AmbiguousError is returned by FindE when more than one
+// command matches the name.
+type AmbiguousError struct {
+	Name       string
+	Candidates []Commander
+}
+
+func (e *AmbiguousError) Error() string {
+	names := make([]string, 0, len(e.Candidates))
+	for _, c := range e.Candidates {
+		names = append(names, c.PluginName())
+	}
+	return fmt.Sprintf("ambiguous command %q matches: %s", e.Name, strings.Join(names, ", "))
+}
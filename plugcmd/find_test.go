@@ -2,6 +2,7 @@ package plugcmd
 
 import (
 	"context"
+	"errors"
 	"testing"
 
 	"github.com/markbates/plugins"
@@ -79,3 +80,75 @@ func Test_FindFromArgs(t *testing.T) {
 	r.NotNil(p)
 	r.Equal(m, p)
 }
+
+func Test_FindE(t *testing.T) {
+	t.Parallel()
+	r := require.New(t)
+
+	build := mega{name: "x/build", cmdName: "build", aliases: []string{"b"}}
+	bench := mega{name: "x/bench", cmdName: "bench"}
+	plugs := plugins.Plugins{
+		stringPlug("a"),
+		build,
+		bench,
+		mega{name: "x/test", cmdName: "test", aliases: []string{"t"}},
+	}
+
+	c, err := FindE("b", plugs)
+	r.NoError(err)
+	r.Equal(build, c)
+
+	_, err = FindE("biuld", plugs)
+	r.Error(err)
+	r.ErrorIs(err, ErrNotFound)
+	r.Equal(`unknown command "biuld", did you mean "build"?`, err.Error())
+
+	var nf *NotFoundError
+	r.True(errors.As(err, &nf))
+	r.Equal("biuld", nf.Name)
+	r.Equal([]string{"build"}, nf.Suggestions)
+
+	_, err = FindE("benc", plugs)
+	r.True(errors.As(err, &nf))
+	r.Equal([]string{"bench"}, nf.Suggestions)
+
+	_, err = FindE("zzzzzz", plugs)
+	r.True(errors.As(err, &nf))
+	r.Empty(nf.Suggestions)
+	r.Equal(`unknown command "zzzzzz"`, err.Error())
+
+	plugs = append(plugs, mega{name: "y/build", cmdName: "make", aliases: []string{"b"}})
+
+	_, err = FindE("b", plugs)
+	r.Error(err)
+
+	var ae *AmbiguousError
+	r.True(errors.As(err, &ae))
+	r.Len(ae.Candidates, 2)
+	r.Equal(`ambiguous command "b" matches: x/build, y/build`, err.Error())
+
+	// Find keeps returning the first match
+	r.Equal(build, Find("b", plugs))
+}
+
+func Test_distance(t *testing.T) {
+	t.Parallel()
+	r := require.New(t)
+
+	table := []struct {
+		a   string
+		b   string
+		exp int
+	}{
+		{"", "", 0},
+		{"build", "build", 0},
+		{"biuld", "build", 1},
+		{"buld", "build", 1},
+		{"kitten", "sitting", 3},
+		{"", "abc", 3},
+	}
+
+	for _, tt := range table {
+		r.Equal(tt.exp, distance(tt.a, tt.b), "%s -> %s", tt.a, tt.b)
+	}
+}
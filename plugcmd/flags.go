@@ -43,6 +43,88 @@ func Clean(p plugins.Plugin, flags []*flag.Flag) []*flag.Flag {
 	return fls
 }
 
+// MergeFlags builds a single flag set from the flags of
+// main's Flagger, as is, and the flags of every Flagger in
+// plugs, namespaced with Clean. Parsing the set fills in
+// the original flag.Value of each plugin.
+//
+// An error is returned if two flags end up with the same
+// name. The set's Usage prints the flags grouped by the
+// plugin they came from.
+func MergeFlags(main plugins.Plugin, plugs plugins.Plugins) (*flag.FlagSet, error) {
+	set := flag.NewFlagSet(cmdName(main), flag.ContinueOnError)
+
+	type group struct {
+		title string
+		flags []*flag.Flag
+	}
+
+	var groups []group
+	owners := map[string]plugins.Plugin{}
+
+	add := func(p plugins.Plugin, title string, flags []*flag.Flag) error {
+		for _, f := range flags {
+			if o, ok := owners[f.Name]; ok {
+				return fmt.Errorf("flag %q of %s collides with %s", f.Name, p.PluginName(), o.PluginName())
+			}
+			owners[f.Name] = p
+
+			set.Var(f.Value, f.Name, f.Usage)
+			set.Lookup(f.Name).DefValue = f.DefValue
+		}
+
+		if len(flags) > 0 {
+			groups = append(groups, group{title: title, flags: flags})
+		}
+		return nil
+	}
+
+	if f, ok := main.(Flagger); ok {
+		fs, err := f.Flags()
+		if err != nil {
+			return nil, err
+		}
+
+		if err := add(main, "", SetToSlice(fs)); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, f := range plugins.ByType[Flagger](plugs) {
+		fs, err := f.Flags()
+		if err != nil {
+			return nil, err
+		}
+
+		if err := add(f, path.Base(name(f)), CleanSet(f, fs)); err != nil {
+			return nil, err
+		}
+	}
+
+	set.Usage = func() {
+		out := set.Output()
+		fmt.Fprintf(out, "Usage of %s:\n", set.Name())
+
+		for _, g := range groups {
+			if len(g.title) > 0 {
+				fmt.Fprintf(out, "\n%s flags:\n", g.title)
+			}
+
+			// a set per group, so PrintDefaults can
+			// do the formatting
+			gs := flag.NewFlagSet(g.title, flag.ContinueOnError)
+			gs.SetOutput(out)
+			for _, f := range g.flags {
+				gs.Var(f.Value, f.Name, f.Usage)
+				gs.Lookup(f.Name).DefValue = f.DefValue
+			}
+			gs.PrintDefaults()
+		}
+	}
+
+	return set, nil
+}
+
 func name(p plugins.Plugin) string {
 	if c, ok := p.(Namer); ok {
 		return c.CmdName()
@@ -1,9 +1,11 @@
 package plugcmd
 
 import (
+	"bytes"
 	"flag"
 	"testing"
 
+	"github.com/markbates/plugins"
 	"github.com/stretchr/testify/require"
 )
 
@@ -64,3 +66,87 @@ func Test_CleanFlagSet(t *testing.T) {
 	act := f.Name
 	r.Equal(exp, act)
 }
+
+func Test_MergeFlags(t *testing.T) {
+	t.Parallel()
+	r := require.New(t)
+
+	var race bool
+	main := runFlagCmd{runSubCmd{&runCmd{
+		name: "build",
+		flags: func() *flag.FlagSet {
+			set := flag.NewFlagSet("build", flag.ContinueOnError)
+			set.BoolVar(&race, "race", false, "enable the race detector")
+			return set
+		},
+	}}}
+
+	var mode string
+	var level int
+	plugs := plugins.Plugins{
+		namedFlagger{name: "x/vet", set: func() *flag.FlagSet {
+			set := flag.NewFlagSet("", flag.ContinueOnError)
+			set.StringVar(&mode, "mode", "fast", "vet mode")
+			return set
+		}},
+		stringPlugin("not a flagger"),
+		namedFlagger{name: "y/lint", set: func() *flag.FlagSet {
+			set := flag.NewFlagSet("", flag.ContinueOnError)
+			set.IntVar(&level, "level", 1, "lint level")
+			return set
+		}},
+	}
+
+	set, err := MergeFlags(main, plugs)
+	r.NoError(err)
+	r.Equal("build", set.Name())
+
+	err = set.Parse([]string{"-race", "-vet-mode", "slow", "-lint-level=3", "./..."})
+	r.NoError(err)
+
+	r.True(race)
+	r.Equal("slow", mode)
+	r.Equal(3, level)
+	r.Equal([]string{"./..."}, set.Args())
+
+	bb := &bytes.Buffer{}
+	set.SetOutput(bb)
+	set.Usage()
+
+	exp := `Usage of build:
+  -race
+    	enable the race detector
+
+vet flags:
+  -vet-mode string
+    	vet mode (default "fast")
+
+lint flags:
+  -lint-level int
+    	lint level (default 1)
+`
+	r.Equal(exp, bb.String())
+}
+
+func Test_MergeFlags_Collision(t *testing.T) {
+	t.Parallel()
+	r := require.New(t)
+
+	main := FlaggerFn(func() (*flag.FlagSet, error) {
+		set := flag.NewFlagSet("", flag.ContinueOnError)
+		set.String("vet-mode", "", "")
+		return set, nil
+	})
+
+	plugs := plugins.Plugins{
+		namedFlagger{name: "x/vet", set: func() *flag.FlagSet {
+			set := flag.NewFlagSet("", flag.ContinueOnError)
+			set.String("mode", "", "")
+			return set
+		}},
+	}
+
+	_, err := MergeFlags(main, plugs)
+	r.Error(err)
+	r.Equal(`flag "vet-mode" of x/vet collides with plugcmd.FlaggerFn`, err.Error())
+}
@@ -0,0 +1,122 @@
+package plugcmd
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/markbates/plugins"
+)
+
+// Group is a section of the Available Commands. Groups
+// are listed by Order, then Title. Commands in groups
+// with the same ID are listed together.
+type Group struct {
+	ID    string `json:"id"`
+	Title string `json:"title,omitempty"` // defaults to the ID
+	Order int    `json:"order,omitempty"`
+}
+
+// DefaultGroupTitle is the title of the section that holds
+// the commands that are not in a group, when any are.
+const DefaultGroupTitle = "Other Commands"
+
+// Grouper is a command that belongs to a Group. Print,
+// generated docs, and completions list the commands of a
+// SubCommander under the titles of their groups, followed
+// by any commands that are not in a group.
+type Grouper interface {
+	plugins.Plugin
+	CmdGroup() Group
+}
+
+var _ Grouper = GrouperFn(nil)
+
+// GrouperFn is a function that can be used to implement the Grouper interface
+type GrouperFn func() Group
+
+func (fn GrouperFn) CmdGroup() Group {
+	return fn()
+}
+
+func (fn GrouperFn) PluginName() string {
+	return fmt.Sprintf("%T", fn)
+}
+
+// cmdGroup returns the plugin's group, with its Title
+// defaulted, or the zero Group if it has none.
+func cmdGroup(p plugins.Plugin) Group {
+	g, ok := plugins.As[Grouper](p)
+	if !ok {
+		return Group{}
+	}
+
+	grp := g.CmdGroup()
+	if len(grp.ID) == 0 {
+		return Group{}
+	}
+
+	if len(grp.Title) == 0 {
+		grp.Title = grp.ID
+	}
+	return grp
+}
+
+// itemGroup is a Group, and the items in it.
+type itemGroup[T any] struct {
+	Group
+	items []T
+}
+
+// groupCommands sorts the commands into their groups.
+func groupCommands(cmds []HelpCommand) []itemGroup[HelpCommand] {
+	return groupItems(cmds, func(c HelpCommand) Group {
+		return c.Group
+	})
+}
+
+// groupItems sorts the items into their groups, keeping
+// their order within each group. The items that are not
+// in a group come last, under the DefaultGroupTitle. If
+// no item is in a group, a single group, with no title,
+// is returned.
+func groupItems[T any](items []T, group func(T) Group) []itemGroup[T] {
+	var res []itemGroup[T]
+	var other []T
+
+	index := map[string]int{}
+	for _, it := range items {
+		g := group(it)
+		if len(g.ID) == 0 {
+			other = append(other, it)
+			continue
+		}
+
+		i, ok := index[g.ID]
+		if !ok {
+			i = len(res)
+			index[g.ID] = i
+			res = append(res, itemGroup[T]{Group: g})
+		}
+		res[i].items = append(res[i].items, it)
+	}
+
+	if len(res) == 0 {
+		return []itemGroup[T]{{items: other}}
+	}
+
+	sort.SliceStable(res, func(i, j int) bool {
+		if res[i].Order != res[j].Order {
+			return res[i].Order < res[j].Order
+		}
+		return res[i].Title < res[j].Title
+	})
+
+	if len(other) > 0 {
+		res = append(res, itemGroup[T]{
+			Group: Group{Title: DefaultGroupTitle},
+			items: other,
+		})
+	}
+
+	return res
+}
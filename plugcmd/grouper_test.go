@@ -0,0 +1,131 @@
+package plugcmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type groupCmd struct {
+	*runCmd
+	desc  string
+	group Group
+}
+
+func (c groupCmd) Description() string {
+	return c.desc
+}
+
+func (c groupCmd) CmdGroup() Group {
+	return c.group
+}
+
+func groupTree() runSubCmd {
+	build := Group{ID: "build", Title: "Build", Order: 1}
+	db := Group{ID: "db", Title: "Database", Order: 2}
+
+	return runSubCmd{&runCmd{
+		name: "app",
+		subs: []Commander{
+			groupCmd{runCmd: &runCmd{name: "migrate"}, desc: "Run migrations", group: db},
+			groupCmd{runCmd: &runCmd{name: "build"}, desc: "Build the app", group: build},
+			groupCmd{runCmd: &runCmd{name: "install"}, desc: "Install the app", group: build},
+			groupCmd{runCmd: &runCmd{name: "version"}, desc: "Print the version"},
+			groupCmd{runCmd: &runCmd{name: "plugins"}, desc: "List plugins", group: Group{ID: "plugins", Order: 3}},
+		},
+	}}
+}
+
+func Test_GrouperFn(t *testing.T) {
+	t.Parallel()
+	r := require.New(t)
+
+	exp := Group{ID: "build", Title: "Build", Order: 1}
+	fn := GrouperFn(func() Group {
+		return exp
+	})
+
+	r.Equal(exp, fn.CmdGroup())
+	r.Equal(exp, cmdGroup(fn))
+	r.Equal(Group{ID: "x", Title: "x"}, cmdGroup(GrouperFn(func() Group {
+		return Group{ID: "x"}
+	})))
+	r.Equal(Group{}, cmdGroup(stringPlug("none")))
+
+	r.Equal(fmt.Sprintf("%T", fn), fn.PluginName())
+}
+
+func Test_Grouper_Print(t *testing.T) {
+	t.Parallel()
+	r := require.New(t)
+
+	bb := &bytes.Buffer{}
+	r.NoError(PrintWith(bb, groupTree(), PrintOptions{HideType: true}))
+
+	exp := `$ app
+-----
+
+Available Commands:
+
+Build:
+  build    Build the app
+  install  Install the app
+
+Database:
+  migrate  Run migrations
+
+plugins:
+  plugins  List plugins
+
+Other Commands:
+  version  Print the version
+`
+	r.Equal(exp, bb.String())
+}
+
+func Test_Grouper_Term(t *testing.T) {
+	t.Parallel()
+	r := require.New(t)
+
+	bb := &bytes.Buffer{}
+	r.NoError(PrintTerm(bb, groupTree(), TermOptions{Width: 80}))
+
+	act := bb.String()
+	r.Contains(act, "\nBuild:\n  build    Build the app\n  install  Install the app\n")
+	r.Contains(act, "\nOther Commands:\n  version  Print the version\n")
+	r.NotContains(act, "Command  Description")
+}
+
+func Test_Grouper_Docs(t *testing.T) {
+	t.Parallel()
+	r := require.New(t)
+
+	dir := t.TempDir()
+	r.NoError(GenMarkdownTree(groupTree(), dir))
+
+	b, err := os.ReadFile(filepath.Join(dir, "app.md"))
+	r.NoError(err)
+	r.Contains(string(b), "## Available Commands\n\n### Build\n\n| Command | Description |\n| ------- | ----------- |\n| [app build](app_build.md) | Build the app |\n| [app install](app_install.md) | Install the app |\n\n### Database\n")
+	r.Contains(string(b), "### Other Commands\n\n| Command | Description |\n| ------- | ----------- |\n| [app version](app_version.md) | Print the version |\n")
+
+	r.NoError(GenManTree(groupTree(), dir))
+
+	b, err = os.ReadFile(filepath.Join(dir, "app.1"))
+	r.NoError(err)
+	r.Contains(string(b), ".SH COMMANDS\n.SS Build\n.TP\n\\fBapp build\\fR\nBuild the app\n")
+}
+
+func Test_Grouper_Complete(t *testing.T) {
+	t.Parallel()
+	r := require.New(t)
+
+	bb := &bytes.Buffer{}
+	r.NoError(printCompletions(bb, completions([]string{""}, groupTree())))
+
+	exp := "migrate\t[Database] Run migrations\nbuild\t[Build] Build the app\ninstall\t[Build] Install the app\nversion\tPrint the version\nplugins\t[plugins] List plugins\n"
+	r.Equal(exp, bb.String())
+}
@@ -0,0 +1,65 @@
+package plugcmd
+
+import (
+	"context"
+
+	"github.com/markbates/plugins"
+)
+
+var _ ArgsSpec = &Help{}
+var _ Commander = &Help{}
+var _ Describer = &Help{}
+var _ Namer = &Help{}
+var _ plugins.IOSetable = &Help{}
+var _ plugins.Needer = &Help{}
+
+// Help is the built-in `help [cmd...]` command. It finds
+// the command at the path given, starting with the
+// Commanders in the collection, and prints its help with
+// Print, including the user aliases that run it.
+type Help struct {
+	builtin
+}
+
+func (h *Help) PluginName() string {
+	return "plugcmd/help"
+}
+
+func (h *Help) CmdName() string {
+	return "help"
+}
+
+func (h *Help) Description() string {
+	return "Print the help for a command"
+}
+
+func (h *Help) ArgsSpec() []Arg {
+	return []Arg{
+		Variadic("command", 0, Unlimited),
+	}
+}
+
+func (h *Help) Main(ctx context.Context, root string, args []string) error {
+	plugs, err := h.plugins(h.PluginName())
+	if err != nil {
+		return err
+	}
+
+	top := runRoot(plugs)
+
+	node, err := findPath(top, args)
+	if err != nil {
+		PrintError(h.stderr(plugs), err)
+		return err
+	}
+
+	opts := PrintOptions{
+		UserAliases: aliasesFor(userAliasList(plugs), args),
+	}
+
+	if len(args) > 0 {
+		opts.Path = append([]string{cmdName(top)}, args[:len(args)-1]...)
+	}
+
+	return PrintWith(h.stdout(plugs), node, opts)
+}
@@ -0,0 +1,29 @@
+package plugcmd
+
+import (
+	"fmt"
+
+	"github.com/markbates/plugins"
+)
+
+// HelpTemplater can be implemented by a Commander to
+// render its help, with Print and PrintWith, using its
+// own `text/template`. The template is executed with the
+// plugin's *HelpModel and has access to HelpFuncs.
+type HelpTemplater interface {
+	plugins.Plugin
+	HelpTemplate() string
+}
+
+var _ HelpTemplater = HelpTemplaterFn(nil)
+
+// HelpTemplaterFn is a function that can be used to implement the HelpTemplater interface
+type HelpTemplaterFn func() string
+
+func (fn HelpTemplaterFn) HelpTemplate() string {
+	return fn()
+}
+
+func (fn HelpTemplaterFn) PluginName() string {
+	return fmt.Sprintf("%T", fn)
+}
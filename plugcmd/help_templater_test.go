@@ -0,0 +1,21 @@
+package plugcmd
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_HelpTemplaterFn(t *testing.T) {
+	t.Parallel()
+	r := require.New(t)
+
+	exp := "{{.Name}}"
+	fn := HelpTemplaterFn(func() string {
+		return exp
+	})
+
+	r.Equal(exp, fn.HelpTemplate())
+	r.Equal(fmt.Sprintf("%T", fn), fn.PluginName())
+}
@@ -0,0 +1,63 @@
+package plugcmd
+
+import (
+	"context"
+	"testing"
+
+	"github.com/markbates/iox"
+	"github.com/markbates/plugins"
+	"github.com/stretchr/testify/require"
+)
+
+func builtinPlugs(t *testing.T) (plugins.Plugins, *iox.Buffer) {
+	t.Helper()
+
+	plugs, _, _, _ := runTree(t)
+	plugs = append(plugs, Builtins()...)
+	plugs = append(plugs, UserAliaserFn(func() []UserAlias {
+		return []UserAlias{
+			{Name: "ci", Steps: [][]string{{"a", "b", "-v"}}},
+		}
+	}))
+
+	bb := &iox.Buffer{}
+	require.NoError(t, plugs.SetStdio(bb.IO()))
+	require.NoError(t, plugs.WithPlugins(plugs.PluginFeeder()))
+
+	return plugs, bb
+}
+
+func Test_Help(t *testing.T) {
+	t.Parallel()
+	r := require.New(t)
+
+	plugs, bb := builtinPlugs(t)
+	ctx := context.Background()
+
+	r.NoError(Run(ctx, "", []string{"help"}, plugs))
+	out := bb.Out.String()
+	r.Contains(out, "Available Commands:")
+	r.Contains(out, "help     Print the help for a command")
+	r.Contains(out, "info     Print diagnostic information (useful for debugging)")
+	r.Contains(out, "version  Print the version information")
+	r.Contains(out, "User Aliases:")
+
+	bb.Out.Reset()
+	r.NoError(Run(ctx, "", []string{"help", "a", "b"}, plugs))
+	out = bb.Out.String()
+	r.Contains(out, "$ b\n")
+	r.Contains(out, "-v\tverbose")
+	r.Contains(out, "ci     a b -v")
+
+	bb.Out.Reset()
+	r.NoError(Run(ctx, "", []string{"help", "help"}, plugs))
+	r.Contains(bb.Out.String(), "Usage: "+cmdName(runRoot(plugs))+" help [command...]")
+
+	err := Run(ctx, "", []string{"help", "a", "nope"}, plugs)
+	r.ErrorIs(err, ErrNotFound)
+	r.Equal(ExitUsage, ExitCode(err))
+	r.Contains(bb.Err.String(), `Unknown command "nope".`)
+
+	err = (&Help{}).Main(ctx, "", nil)
+	r.Error(err)
+}
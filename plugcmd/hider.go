@@ -0,0 +1,45 @@
+package plugcmd
+
+import (
+	"fmt"
+
+	"github.com/markbates/plugins"
+)
+
+// Hider is a command that can hide itself from the
+// Available Commands of Print, from completions, and from
+// generated docs. A hidden command can still be run, and
+// its help printed, by name.
+type Hider interface {
+	plugins.Plugin
+	CmdHidden() bool
+}
+
+var _ Hider = HiderFn(nil)
+
+// HiderFn is a function that can be used to implement the Hider interface
+type HiderFn func() bool
+
+func (fn HiderFn) CmdHidden() bool {
+	return fn()
+}
+
+func (fn HiderFn) PluginName() string {
+	return fmt.Sprintf("%T", fn)
+}
+
+func hidden(p plugins.Plugin) bool {
+	h, ok := plugins.As[Hider](p)
+	return ok && h.CmdHidden()
+}
+
+// visibleCommands returns the commands that are not hidden.
+func visibleCommands(cmds []Commander) []Commander {
+	res := make([]Commander, 0, len(cmds))
+	for _, c := range cmds {
+		if !hidden(c) {
+			res = append(res, c)
+		}
+	}
+	return res
+}
@@ -0,0 +1,85 @@
+package plugcmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/markbates/iox"
+	"github.com/markbates/plugins"
+	"github.com/markbates/plugins/plugtest"
+	"github.com/stretchr/testify/require"
+)
+
+type hiddenCmd struct {
+	*runCmd
+}
+
+func (hiddenCmd) CmdHidden() bool {
+	return true
+}
+
+func Test_HiderFn(t *testing.T) {
+	t.Parallel()
+	r := require.New(t)
+
+	fn := HiderFn(func() bool {
+		return true
+	})
+
+	r.True(fn.CmdHidden())
+	r.True(hidden(fn))
+	r.False(hidden(stringPlug("visible")))
+
+	r.Equal(fmt.Sprintf("%T", fn), fn.PluginName())
+}
+
+func Test_Hider(t *testing.T) {
+	t.Parallel()
+	r := require.New(t)
+
+	var ran bool
+	secret := hiddenCmd{&runCmd{
+		name: "secret",
+		fn: func(args []string) error {
+			ran = true
+			return nil
+		},
+	}}
+
+	app := runSubCmd{&runCmd{
+		name: "app",
+		subs: []Commander{&runCmd{name: "build"}, secret},
+	}}
+
+	bb := &bytes.Buffer{}
+	r.NoError(Print(bb, app))
+	r.Contains(bb.String(), "build")
+	r.NotContains(bb.String(), "secret")
+
+	var names []string
+	for _, c := range completions([]string{""}, app) {
+		names = append(names, c.value)
+	}
+	r.Equal([]string{"build"}, names)
+	r.Empty(suggest("secre", app.subs))
+
+	// hidden commands can still be run
+	io := &iox.Buffer{}
+	plugs := plugins.Plugins{app, &plugtest.IO{IO: io.IO()}}
+	r.NoError(Run(context.Background(), "", []string{"app", "secret"}, plugs))
+	r.True(ran)
+
+	// and keep hidden when wrapped
+	r.True(hidden(Wrap(secret, Recover())))
+
+	dir := t.TempDir()
+	r.NoError(GenMarkdownTree(app, dir))
+	_, err := os.Stat(filepath.Join(dir, "app_build.md"))
+	r.NoError(err)
+	_, err = os.Stat(filepath.Join(dir, "app_secret.md"))
+	r.ErrorIs(err, os.ErrNotExist)
+}
@@ -0,0 +1,195 @@
+package plugcmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/markbates/plugins"
+)
+
+var _ Commander = &Info{}
+var _ Describer = &Info{}
+var _ Namer = &Info{}
+var _ plugins.IOSetable = &Info{}
+var _ plugins.Needer = &Info{}
+
+// Info is the built-in `info` command. It prints every
+// plugin in the collection, sorted by name, with its Go
+// type, the plugins, and plugcmd, interfaces it
+// implements, if it is available at root, and if its IO,
+// and file system, have been set.
+//
+//	plugcmd/version
+//	  Type:        *github.com/markbates/plugins/plugcmd.Version
+//	  Available:   yes
+//	  IO:          set
+//	  FS:          n/a
+//	  Interfaces:  Commander, Describer, Namer, plugins.IOSetable, plugins.IOable, plugins.Needer
+//
+// IO, and FS, can only be checked for plugins that
+// return them, with plugins.IOable, or plugins.FSable.
+type Info struct {
+	builtin
+}
+
+func (i *Info) PluginName() string {
+	return "plugcmd/info"
+}
+
+func (i *Info) CmdName() string {
+	return "info"
+}
+
+func (i *Info) Description() string {
+	return "Print diagnostic information (useful for debugging)"
+}
+
+func (i *Info) Main(ctx context.Context, root string, args []string) error {
+	plugs, err := i.plugins(i.PluginName())
+	if err != nil {
+		return err
+	}
+
+	return printInfo(i.stdout(plugs), root, plugs)
+}
+
+func printInfo(w io.Writer, root string, plugs plugins.Plugins) error {
+	sorted := append(plugins.Plugins{}, plugs...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].PluginName() < sorted[j].PluginName()
+	})
+
+	available := map[string]bool{}
+	for _, p := range plugs.Available(root) {
+		available[p.PluginName()] = true
+	}
+
+	printHeader(w, "info")
+	fmt.Fprintf(w, "Root: %s\n", root)
+
+	for _, p := range sorted {
+		if p == nil {
+			continue
+		}
+
+		fmt.Fprintf(w, "\n%s\n", p.PluginName())
+
+		// report on the command given to Wrap
+		p = unwrapPlugin(p)
+
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintf(tw, "\tType:\t%s\n", typeName(p))
+		fmt.Fprintf(tw, "\tAvailable:\t%s\n", yesNo(available[p.PluginName()]))
+		fmt.Fprintf(tw, "\tIO:\t%s\n", ioState(p))
+		fmt.Fprintf(tw, "\tFS:\t%s\n", fsState(p))
+		fmt.Fprintf(tw, "\tInterfaces:\t%s\n", strings.Join(implemented(p), ", "))
+		if err := tw.Flush(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
+
+func ioState(p plugins.Plugin) string {
+	if s, ok := p.(plugins.IOable); ok {
+		oi := s.Stdio()
+		if oi.In == nil && oi.Out == nil && oi.Err == nil {
+			return "not set"
+		}
+		return "set"
+	}
+
+	if _, ok := p.(plugins.IOSetable); ok {
+		return "unknown"
+	}
+	return "n/a"
+}
+
+func fsState(p plugins.Plugin) string {
+	if s, ok := p.(plugins.FSable); ok {
+		fsys, err := s.FileSystem()
+		if err != nil || fsys == nil {
+			return "not set"
+		}
+		return "set"
+	}
+
+	if _, ok := p.(plugins.FSSetable); ok {
+		return "unknown"
+	}
+	return "n/a"
+}
+
+// knownInterface is an interface, from plugins, or
+// plugcmd, reported by Info.
+type knownInterface struct {
+	name string
+	is   func(p plugins.Plugin) bool
+}
+
+func is[T any](p plugins.Plugin) bool {
+	_, ok := plugins.As[T](p)
+	return ok
+}
+
+// knownInterfaces are sorted by name, plugcmd first.
+var knownInterfaces = []knownInterface{
+	{name: "AfterRunner", is: is[AfterRunner]},
+	{name: "Aliaser", is: is[Aliaser]},
+	{name: "ArgsSpec", is: is[ArgsSpec]},
+	{name: "BeforeRunner", is: is[BeforeRunner]},
+	{name: "Commander", is: is[Commander]},
+	{name: "Completer", is: is[Completer]},
+	{name: "ConfigFiler", is: is[ConfigFiler]},
+	{name: "Deprecator", is: is[Deprecator]},
+	{name: "Describer", is: is[Describer]},
+	{name: "Experimental", is: is[Experimental]},
+	{name: "FlagPrinter", is: is[FlagPrinter]},
+	{name: "Flagger", is: is[Flagger]},
+	{name: "Grouper", is: is[Grouper]},
+	{name: "HelpTemplater", is: is[HelpTemplater]},
+	{name: "Hider", is: is[Hider]},
+	{name: "Namer", is: is[Namer]},
+	{name: "Shorthander", is: is[Shorthander]},
+	{name: "SubCommander", is: is[SubCommander]},
+	{name: "TermOptioner", is: is[TermOptioner]},
+	{name: "UsagePrinter", is: is[UsagePrinter]},
+	{name: "UserAliaser", is: is[UserAliaser]},
+	{name: "plugins.AvailabilityChecker", is: is[plugins.AvailabilityChecker]},
+	{name: "plugins.FSSetable", is: is[plugins.FSSetable]},
+	{name: "plugins.FSable", is: is[plugins.FSable]},
+	{name: "plugins.Feeder", is: is[plugins.Feeder]},
+	{name: "plugins.IOSetable", is: is[plugins.IOSetable]},
+	{name: "plugins.IOable", is: is[plugins.IOable]},
+	{name: "plugins.Initializer", is: is[plugins.Initializer]},
+	{name: "plugins.Needer", is: is[plugins.Needer]},
+	{name: "plugins.Requirer", is: is[plugins.Requirer]},
+	{name: "plugins.Scoper", is: is[plugins.Scoper]},
+	{name: "plugins.Starter", is: is[plugins.Starter]},
+	{name: "plugins.Stderrer", is: is[plugins.Stderrer]},
+	{name: "plugins.Stdiner", is: is[plugins.Stdiner]},
+	{name: "plugins.Stdouter", is: is[plugins.Stdouter]},
+	{name: "plugins.Stopper", is: is[plugins.Stopper]},
+}
+
+func implemented(p plugins.Plugin) []string {
+	var res []string
+	for _, ki := range knownInterfaces {
+		if ki.is(p) {
+			res = append(res, ki.name)
+		}
+	}
+	return res
+}
@@ -0,0 +1,78 @@
+package plugcmd
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// unavailable is only available at "/here".
+type unavailable string
+
+func (u unavailable) PluginName() string {
+	return string(u)
+}
+
+func (u unavailable) PluginAvailable(root string) bool {
+	return root == "/here"
+}
+
+func Test_Info(t *testing.T) {
+	t.Parallel()
+	r := require.New(t)
+
+	plugs, bb := builtinPlugs(t)
+	plugs = append(plugs, unavailable("zzz"), &Shell{})
+	r.NoError(plugs.WithPlugins(plugs.PluginFeeder()))
+
+	r.NoError(Run(context.Background(), "/root", []string{"info"}, plugs))
+
+	out := bb.Out.String()
+	r.True(strings.HasPrefix(out, "$ info\n------\nRoot: /root\n"))
+
+	r.Contains(out, `
+plugcmd/info
+  Type:        *github.com/markbates/plugins/plugcmd.Info
+  Available:   yes
+  IO:          set
+  FS:          n/a
+  Interfaces:  Commander, Describer, Namer, plugins.IOSetable, plugins.IOable, plugins.Needer
+`)
+
+	r.Contains(out, `
+plugcmd/shell
+  Type:        *github.com/markbates/plugins/plugcmd.Shell
+  Available:   yes
+  IO:          unknown
+  FS:          unknown
+`)
+
+	r.Contains(out, `
+zzz
+  Type:        github.com/markbates/plugins/plugcmd.unavailable
+  Available:   no
+  IO:          n/a
+  FS:          n/a
+  Interfaces:  plugins.AvailabilityChecker
+`)
+
+	r.Less(strings.Index(out, "plugcmd/help"), strings.Index(out, "plugcmd/info"))
+
+	r.Error((&Info{}).Main(context.Background(), "", nil))
+}
+
+func Test_Info_Interfaces(t *testing.T) {
+	t.Parallel()
+	r := require.New(t)
+
+	names := make([]string, 0, len(knownInterfaces))
+	for _, ki := range knownInterfaces {
+		names = append(names, ki.name)
+	}
+	r.IsIncreasing(names)
+
+	r.Equal([]string{"Commander", "Namer", "SubCommander"}, implemented(runSubCmd{}))
+	r.Empty(implemented(stringPlug("nothing")))
+}
@@ -0,0 +1,98 @@
+// wrapgen writes wrap_gen.go, the types returned by
+// plugcmd.Wrap for every combination of the optional
+// interfaces a Commander can implement.
+//
+//	go generate ./plugcmd
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"strings"
+)
+
+type iface struct {
+	name   string
+	method string
+	result string
+}
+
+var ifaces = []iface{
+	{name: "Namer", method: "CmdName", result: "string"},
+	{name: "Aliaser", method: "CmdAliases", result: "[]string"},
+	{name: "Describer", method: "Description", result: "string"},
+	{name: "Flagger", method: "Flags", result: "(*flag.FlagSet, error)"},
+	{name: "SubCommander", method: "SubCommands", result: "[]Commander"},
+}
+
+func main() {
+	out := "wrap_gen.go"
+	if len(os.Args) > 1 {
+		out = os.Args[1]
+	}
+
+	bb := &bytes.Buffer{}
+	fmt.Fprintln(bb, "// Code generated by wrapgen. DO NOT EDIT.")
+	fmt.Fprintln(bb)
+	fmt.Fprintln(bb, "package plugcmd")
+	fmt.Fprintln(bb)
+	fmt.Fprintln(bb, `import "flag"`)
+	fmt.Fprintln(bb)
+
+	combos := 1 << len(ifaces)
+
+	fmt.Fprintln(bb, "// wrapFor returns w as a type that implements")
+	fmt.Fprintln(bb, "// the same optional interfaces as w.cmd.")
+	fmt.Fprintln(bb, "func wrapFor(w *wrapper) Commander {")
+	fmt.Fprintln(bb, "\tvar mask int")
+	for i, in := range ifaces {
+		fmt.Fprintf(bb, "\tif _, ok := w.cmd.(%s); ok {\n\t\tmask |= %d\n\t}\n", in.name, 1<<i)
+	}
+	fmt.Fprintln(bb)
+	fmt.Fprintln(bb, "\tswitch mask {")
+	for m := 1; m < combos; m++ {
+		fmt.Fprintf(bb, "\tcase %d:\n\t\treturn %s{w}\n", m, typeName(m))
+	}
+	fmt.Fprintln(bb, "\t}")
+	fmt.Fprintln(bb, "\treturn w")
+	fmt.Fprintln(bb, "}")
+
+	for _, in := range ifaces {
+		fmt.Fprintf(bb, "\nfunc (w *wrapper) %s() %s {\n", strings.ToLower(in.method[:1])+in.method[1:], in.result)
+		fmt.Fprintf(bb, "\treturn w.cmd.(%s).%s()\n}\n", in.name, in.method)
+	}
+
+	for m := 1; m < combos; m++ {
+		name := typeName(m)
+		fmt.Fprintf(bb, "\ntype %s struct{ *wrapper }\n", name)
+		for i, in := range ifaces {
+			if m&(1<<i) == 0 {
+				continue
+			}
+			fmt.Fprintf(bb, "\nfunc (w %s) %s() %s {\n", name, in.method, in.result)
+			fmt.Fprintf(bb, "\treturn w.%s()\n}\n", strings.ToLower(in.method[:1])+in.method[1:])
+		}
+	}
+
+	b, err := format.Source(bb.Bytes())
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if err := os.WriteFile(out, b, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func typeName(mask int) string {
+	name := "wrap"
+	for i, in := range ifaces {
+		if mask&(1<<i) != 0 {
+			name += in.name
+		}
+	}
+	return name
+}
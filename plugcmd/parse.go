@@ -0,0 +1,295 @@
+package plugcmd
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/markbates/plugins"
+)
+
+// Parse parses args into the flag set using GNU style
+// syntax, and the short forms from sh, which can be nil.
+//
+//	--verbose, --tags=dev, --tags dev
+//	-v, -t dev, -tdev, -t=dev
+//	-vx         bundled short flags, same as -v -x
+//	--          stops parsing, the rest are positional
+//
+// Positional arguments can be mixed with the flags, and
+// are available, in order, from set.Args. For backward
+// compatibility, long flags can also be given with a
+// single dash, such as `-verbose`.
+//
+// Errors are handled according to the set's
+// flag.ErrorHandling, the same as set.Parse. Unless the
+// set has its own Usage, the usage printed lists the
+// short, and long, form of each flag.
+func Parse(set *flag.FlagSet, sh Shorthander, args []string) error {
+	return parseGNU(set, shorthands(sh), args, true)
+}
+
+func shorthands(p plugins.Plugin) map[rune]string {
+	if sh, ok := plugins.As[Shorthander](p); ok {
+		return sh.Shorthands()
+	}
+	return nil
+}
+
+// parseGNU parses args into set. Unless intersperse is
+// true, parsing stops at the first positional argument.
+func parseGNU(set *flag.FlagSet, shorts map[rune]string, args []string, intersperse bool) error {
+	var pos []string
+
+	err := func() error {
+		for i := 0; i < len(args); i++ {
+			a := args[i]
+			rest := args[i+1:]
+
+			var n int
+			var err error
+
+			switch {
+			case a == "--":
+				pos = append(pos, rest...)
+				return nil
+			case len(a) < 2 || a[0] != '-':
+				if !intersperse {
+					pos = append(pos, args[i:]...)
+					return nil
+				}
+				pos = append(pos, a)
+			case strings.HasPrefix(a, "--"):
+				n, err = parseLong(set, "--", a[2:], rest)
+			default:
+				name, _, _ := strings.Cut(a[1:], "=")
+				if utf8.RuneCountInString(name) > 1 && set.Lookup(name) != nil {
+					n, err = parseLong(set, "-", a[1:], rest)
+					break
+				}
+				n, err = parseShorts(set, shorts, a[1:], rest)
+			}
+
+			if err != nil {
+				return err
+			}
+			i += n
+		}
+		return nil
+	}()
+
+	if err != nil {
+		out := set.Output()
+		if !errors.Is(err, flag.ErrHelp) {
+			fmt.Fprintln(out, err)
+		}
+
+		if customUsage(set) {
+			set.Usage()
+		} else {
+			fmt.Fprintf(out, "Usage of %s:\n", set.Name())
+			printGNUDefaults(out, set, shorts)
+		}
+
+		switch set.ErrorHandling() {
+		case flag.ExitOnError:
+			if errors.Is(err, flag.ErrHelp) {
+				os.Exit(0)
+			}
+			os.Exit(2)
+		case flag.PanicOnError:
+			panic(err)
+		}
+		return err
+	}
+
+	// let the set record the positional
+	// arguments, and that it was parsed
+	return set.Parse(append([]string{"--"}, pos...))
+}
+
+// defaultUsage is the code of the Usage func that
+// flag.NewFlagSet gives every set. It is a method value,
+// so it is the same code for every set.
+var defaultUsage = reflect.ValueOf(flag.NewFlagSet("", flag.ContinueOnError).Usage).Pointer()
+
+// customUsage reports if the set's Usage was replaced,
+// rather than left as the default from flag.NewFlagSet,
+// which only prints the long form of the flags.
+func customUsage(set *flag.FlagSet) bool {
+	if set.Usage == nil {
+		return false
+	}
+	return reflect.ValueOf(set.Usage).Pointer() != defaultUsage
+}
+
+// parseLong parses a single long flag, body is the flag
+// without its dashes. It returns how many of the rest of
+// the args were used as its value.
+func parseLong(set *flag.FlagSet, dash string, body string, rest []string) (int, error) {
+	name, value, hasValue := strings.Cut(body, "=")
+
+	f := set.Lookup(name)
+	if f == nil {
+		if name == "help" || name == "h" {
+			return 0, flag.ErrHelp
+		}
+		return 0, fmt.Errorf("flag provided but not defined: %s%s", dash, name)
+	}
+
+	var n int
+	switch {
+	case hasValue:
+	case isBoolFlag(f):
+		value = "true"
+	case len(rest) == 0:
+		return 0, fmt.Errorf("flag needs an argument: %s%s", dash, name)
+	default:
+		value = rest[0]
+		n = 1
+	}
+
+	if err := set.Set(name, value); err != nil {
+		return 0, fmt.Errorf("invalid value %q for flag %s%s: %v", value, dash, name, err)
+	}
+	return n, nil
+}
+
+// parseShorts parses one or more bundled short flags,
+// body is the flags without the dash. It returns how many
+// of the rest of the args were used as a value.
+func parseShorts(set *flag.FlagSet, shorts map[rune]string, body string, rest []string) (int, error) {
+	runes := []rune(body)
+
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+
+		name := shortName(set, shorts, r)
+		if len(name) == 0 {
+			if r == 'h' {
+				return 0, flag.ErrHelp
+			}
+			return 0, fmt.Errorf("flag provided but not defined: -%c", r)
+		}
+
+		value := string(runes[i+1:])
+		hasValue := len(value) > 0
+
+		if isBoolFlag(set.Lookup(name)) {
+			if !strings.HasPrefix(value, "=") {
+				if err := set.Set(name, "true"); err != nil {
+					return 0, fmt.Errorf("invalid value for flag -%c: %v", r, err)
+				}
+				continue
+			}
+		}
+
+		value = strings.TrimPrefix(value, "=")
+
+		var n int
+		if !hasValue {
+			if len(rest) == 0 {
+				return 0, fmt.Errorf("flag needs an argument: -%c", r)
+			}
+			value = rest[0]
+			n = 1
+		}
+
+		if err := set.Set(name, value); err != nil {
+			return 0, fmt.Errorf("invalid value %q for flag -%c: %v", value, r, err)
+		}
+		return n, nil
+	}
+
+	return 0, nil
+}
+
+// shortName returns the long name of the flag for the
+// short letter, or empty if there isn't one.
+func shortName(set *flag.FlagSet, shorts map[rune]string, r rune) string {
+	if name, ok := shorts[r]; ok && set.Lookup(name) != nil {
+		return name
+	}
+	if set.Lookup(string(r)) != nil {
+		return string(r)
+	}
+	return ""
+}
+
+func isBoolFlag(f *flag.Flag) bool {
+	bf, ok := f.Value.(interface{ IsBoolFlag() bool })
+	return ok && bf.IsBoolFlag()
+}
+
+// printGNUDefaults prints the flags of the set, like
+// set.PrintDefaults, with both their short and long forms.
+//
+//	-v, --verbose
+//	    	be verbose
+//	    --tags string
+//	    	build tags (default "dev")
+func printGNUDefaults(w io.Writer, set *flag.FlagSet, shorts map[rune]string) {
+	letters := map[string]rune{}
+	for r, name := range shorts {
+		letters[name] = r
+	}
+
+	set.VisitAll(func(f *flag.Flag) {
+		bb := &strings.Builder{}
+
+		r, ok := letters[f.Name]
+		switch {
+		case ok:
+			fmt.Fprintf(bb, "  -%c, --%s", r, f.Name)
+		case utf8.RuneCountInString(f.Name) == 1:
+			fmt.Fprintf(bb, "  -%s", f.Name)
+		default:
+			fmt.Fprintf(bb, "      --%s", f.Name)
+		}
+
+		name, usage := flag.UnquoteUsage(f)
+		if len(name) > 0 {
+			fmt.Fprintf(bb, " %s", name)
+		}
+
+		bb.WriteString("\n    \t")
+		bb.WriteString(strings.ReplaceAll(usage, "\n", "\n    \t"))
+
+		if !isZeroValue(f) {
+			if reflect.TypeOf(f.Value).String() == "*flag.stringValue" {
+				fmt.Fprintf(bb, " (default %q)", f.DefValue)
+			} else {
+				fmt.Fprintf(bb, " (default %v)", f.DefValue)
+			}
+		}
+
+		fmt.Fprintln(w, bb.String())
+	})
+}
+
+// isZeroValue reports if the flag's default is the
+// zero value of its type, the same as the flag package.
+func isZeroValue(f *flag.Flag) (ok bool) {
+	defer func() {
+		// some flag.Values can't be created empty
+		if recover() != nil {
+			ok = false
+		}
+	}()
+
+	typ := reflect.TypeOf(f.Value)
+
+	var z reflect.Value
+	if typ.Kind() == reflect.Pointer {
+		z = reflect.New(typ.Elem())
+	} else {
+		z = reflect.Zero(typ)
+	}
+
+	return f.DefValue == z.Interface().(flag.Value).String()
+}
@@ -0,0 +1,219 @@
+package plugcmd
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"io"
+	"testing"
+
+	"github.com/markbates/iox"
+	"github.com/markbates/plugins"
+	"github.com/markbates/plugins/plugtest"
+	"github.com/stretchr/testify/require"
+)
+
+type gnuFlags struct {
+	Verbose bool
+	All     bool
+	Tags    string
+	Count   int
+}
+
+func gnuSet(gf *gnuFlags) *flag.FlagSet {
+	set := flag.NewFlagSet("build", flag.ContinueOnError)
+	set.SetOutput(io.Discard)
+	set.BoolVar(&gf.Verbose, "verbose", false, "be verbose")
+	set.BoolVar(&gf.All, "a", false, "all the things")
+	set.StringVar(&gf.Tags, "tags", "dev", "build `tags`")
+	set.IntVar(&gf.Count, "count", 0, "run count times")
+	return set
+}
+
+var gnuShorts = ShorthanderFn(func() map[rune]string {
+	return map[rune]string{
+		'v': "verbose",
+		't': "tags",
+		'c': "count",
+	}
+})
+
+func Test_Parse(t *testing.T) {
+	t.Parallel()
+
+	table := []struct {
+		name string
+		args []string
+		exp  gnuFlags
+		pos  []string
+	}{
+		{"long", []string{"--verbose", "--tags=prod", "--count", "3"}, gnuFlags{Verbose: true, Tags: "prod", Count: 3}, nil},
+		{"short", []string{"-v", "-t", "prod", "-c3"}, gnuFlags{Verbose: true, Tags: "prod", Count: 3}, nil},
+		{"short equals", []string{"-t=prod", "-v=false"}, gnuFlags{Tags: "prod"}, nil},
+		{"bundled", []string{"-vat", "prod"}, gnuFlags{Verbose: true, All: true, Tags: "prod"}, nil},
+		{"bundled value", []string{"-vc5"}, gnuFlags{Verbose: true, Count: 5, Tags: "dev"}, nil},
+		{"single dash long", []string{"-verbose", "-tags", "prod"}, gnuFlags{Verbose: true, Tags: "prod"}, nil},
+		{"interspersed", []string{"a", "-v", "b", "--tags", "prod", "c"}, gnuFlags{Verbose: true, Tags: "prod"}, []string{"a", "b", "c"}},
+		{"terminator", []string{"-v", "--", "-a", "--tags"}, gnuFlags{Verbose: true, Tags: "dev"}, []string{"-a", "--tags"}},
+		{"dash", []string{"-", "-a"}, gnuFlags{All: true, Tags: "dev"}, []string{"-"}},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			r := require.New(t)
+
+			gf := gnuFlags{}
+			set := gnuSet(&gf)
+
+			r.NoError(Parse(set, gnuShorts, tt.args))
+			r.True(set.Parsed())
+			r.Equal(tt.exp, gf)
+			r.Equal(append([]string{}, tt.pos...), set.Args())
+		})
+	}
+}
+
+func Test_Parse_Errors(t *testing.T) {
+	t.Parallel()
+
+	table := []struct {
+		args []string
+		err  string
+	}{
+		{[]string{"--nope"}, "flag provided but not defined: --nope"},
+		{[]string{"-vx"}, "flag provided but not defined: -x"},
+		{[]string{"--tags"}, "flag needs an argument: --tags"},
+		{[]string{"-t"}, "flag needs an argument: -t"},
+		{[]string{"-c", "x"}, `invalid value "x" for flag -c: parse error`},
+		{[]string{"--count=x"}, `invalid value "x" for flag --count: parse error`},
+		{[]string{"-h"}, flag.ErrHelp.Error()},
+		{[]string{"--help"}, flag.ErrHelp.Error()},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.err, func(t *testing.T) {
+			t.Parallel()
+			r := require.New(t)
+
+			set := gnuSet(&gnuFlags{})
+
+			err := Parse(set, gnuShorts, tt.args)
+			r.Error(err)
+			r.Equal(tt.err, err.Error())
+		})
+	}
+}
+
+func Test_Parse_Usage(t *testing.T) {
+	t.Parallel()
+	r := require.New(t)
+
+	set := gnuSet(&gnuFlags{})
+	bb := &bytes.Buffer{}
+	set.SetOutput(bb)
+
+	r.Error(Parse(set, gnuShorts, []string{"--nope"}))
+
+	exp := `flag provided but not defined: --nope
+Usage of build:
+  -a
+    	all the things
+  -c, --count int
+    	run count times
+  -t, --tags tags
+    	build tags (default "dev")
+  -v, --verbose
+    	be verbose
+`
+	r.Equal(exp, bb.String())
+
+	// no Shorthander
+	set = gnuSet(&gnuFlags{})
+	r.NoError(Parse(set, nil, []string{"-a", "-verbose", "x"}))
+	r.Equal([]string{"x"}, set.Args())
+
+	set = gnuSet(&gnuFlags{})
+	set.SetOutput(bb)
+
+	bb.Reset()
+	r.ErrorIs(Parse(set, gnuShorts, []string{"--help"}), flag.ErrHelp)
+	r.Contains(bb.String(), "  -v, --verbose\n")
+
+	// a set's own Usage is used instead
+	var called bool
+	set.Usage = func() {
+		called = true
+	}
+
+	bb.Reset()
+	r.Error(Parse(set, gnuShorts, []string{"--nope"}))
+	r.True(called)
+	r.Equal("flag provided but not defined: --nope\n", bb.String())
+}
+
+type gnuCmd struct {
+	*runCmd
+	gf *gnuFlags
+}
+
+func (c gnuCmd) Flags() (*flag.FlagSet, error) {
+	return gnuSet(c.gf), nil
+}
+
+func (c gnuCmd) Shorthands() map[rune]string {
+	return gnuShorts()
+}
+
+func Test_Parse_Describe(t *testing.T) {
+	t.Parallel()
+	r := require.New(t)
+
+	m, err := Describe(gnuCmd{runCmd: &runCmd{name: "build"}, gf: &gnuFlags{}})
+	r.NoError(err)
+
+	r.Equal("v", m.Flags[3].Short)
+	r.Equal("verbose", m.Flags[3].Name)
+	r.Empty(m.Flags[0].Short)
+	r.Contains(m.FlagUsage, "Flags:\n")
+	r.Contains(m.FlagUsage, "  -v, --verbose\n")
+	r.Contains(m.FlagUsage, "  -a\n")
+}
+
+func Test_Parse_Run(t *testing.T) {
+	t.Parallel()
+	r := require.New(t)
+
+	gf := &gnuFlags{}
+	var got []string
+	c := gnuCmd{
+		runCmd: &runCmd{
+			name: "build",
+			fn: func(args []string) error {
+				got = args
+				return nil
+			},
+		},
+		gf: gf,
+	}
+
+	bb := &iox.Buffer{}
+	plugs := plugins.Plugins{
+		c,
+		&plugtest.IO{IO: bb.IO()},
+	}
+
+	err := Run(context.Background(), "", []string{"build", "./...", "-vt", "prod"}, plugs)
+	r.NoError(err)
+	r.True(gf.Verbose)
+	r.Equal("prod", gf.Tags)
+	r.Equal([]string{"./..."}, got)
+
+	// positional args after -- are kept
+	r.NoError(Run(context.Background(), "", []string{"build", "-t", "dev", "--", "-x", "y"}, plugs))
+	r.Equal("dev", gf.Tags)
+	r.Equal([]string{"-x", "y"}, got)
+
+	r.NoError(Run(context.Background(), "", []string{"build", "--help"}, plugs))
+	r.Contains(bb.Out.String(), "-t, --tags tags")
+}
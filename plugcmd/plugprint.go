@@ -2,6 +2,7 @@ package plugcmd
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"io"
 	"path"
@@ -15,6 +16,10 @@ import (
 
 // Print will try and print a helpful Usage printing
 // of the plugin and any plugins that are provided.
+// It renders the HelpModel returned by Describe with
+// DefaultHelpTemplate, or the plugin's HelpTemplater.
+// When sub-commands are Groupers, the Available Commands
+// are listed under the titles of their groups.
 //
 //	$ foobar
 //	---------
@@ -26,103 +31,89 @@ import (
 //		foobar info     Print diagnostic information (useful for debugging)
 //		foobar version  Print the version information
 func Print(w io.Writer, main plugins.Plugin) error {
+	return PrintWith(w, main, PrintOptions{})
+}
 
-	header := strings.TrimSpace(cmdName(main))
-	header = fmt.Sprintf("$ %s", header)
-	fmt.Fprintln(w, header)
-	for i := 0; i < len(header); i++ {
-		fmt.Fprint(w, "-")
+// PrintError prints a *NotFoundError or *AmbiguousError,
+// found in err, in the same style as Print. Any other
+// error is printed as is.
+//
+//	$ biuld
+//	-------
+//	Unknown command "biuld".
+//
+//	Did you mean:
+//	  build
+func PrintError(w io.Writer, err error) error {
+	if err == nil {
+		return nil
 	}
-	fmt.Fprintf(w, "\n%s\n", typeName(main))
 
-	if u, ok := main.(UsagePrinter); ok {
-		fmt.Fprintln(w)
-		if err := u.PrintUsage(w); err != nil {
-			return err
-		}
-		fmt.Fprintln(w)
-	}
+	var nf *NotFoundError
+	if errors.As(err, &nf) {
+		printHeader(w, nf.Name)
+		fmt.Fprintf(w, "Unknown command %q.\n", nf.Name)
 
-	if a, ok := main.(Aliaser); ok {
-		aliases := a.CmdAliases()
-		if len(aliases) != 0 {
-			const al = "\nAliases:\n"
-			fmt.Fprint(w, al)
-			fmt.Fprintln(w, strings.Join(aliases, ", "))
+		if len(nf.Suggestions) == 0 {
+			return nil
 		}
-	}
-
-	if err := printFlags(w, main); err != nil {
-		return err
-	}
-
-	if err := printCommands(w, main); err != nil {
-		return err
-	}
 
-	if err := printPlugins(w, main); err != nil {
-		return err
+		fmt.Fprintln(w, "\nDid you mean:")
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		for _, s := range nf.Suggestions {
+			fmt.Fprintf(tw, "\t%s\n", s)
+		}
+		return tw.Flush()
 	}
 
-	return nil
-}
-
-func printFlags(w io.Writer, p plugins.Plugin) error {
-	if u, ok := p.(FlagPrinter); ok {
-		// fmt.Fprintln(w)
-		fmt.Fprintln(w, "\nFlags:")
-		return u.PrintFlags(w)
-	}
+	var ae *AmbiguousError
+	if errors.As(err, &ae) {
+		printHeader(w, ae.Name)
+		fmt.Fprintf(w, "Ambiguous command %q.\n", ae.Name)
 
-	if u, ok := p.(Flagger); ok {
-		flags, err := u.Flags()
-		if err != nil {
-			return err
+		fmt.Fprintln(w, "\nMatching Commands:")
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintf(tw, "\t%s\t%s\t%s\n", "Command", "Description", "Type")
+		fmt.Fprintf(tw, "\t%s\t%s\t%s\n", "-------", "-----------", "----")
+		for _, c := range ae.Candidates {
+			fmt.Fprintf(tw, "\t%s\t%s\t%s\n", cmdName(c), desc(c), typeName(c))
 		}
-
-		ow := flags.Output()
-		flags.SetOutput(w)
-		fmt.Fprintln(w)
-		flags.Usage()
-		flags.SetOutput(ow)
+		return tw.Flush()
 	}
 
-	return nil
+	_, err = fmt.Fprintln(w, err)
+	return err
 }
 
-func printPlugins(w io.Writer, main plugins.Plugin) error {
-	mm := map[string]plugins.Plugin{}
-
-	if err := usingPlugins(main, mm); err != nil {
-		return err
-	}
-
-	if len(mm) == 0 {
-		return nil
+func printHeader(w io.Writer, name string) {
+	header := strings.TrimSpace(name)
+	header = fmt.Sprintf("$ %s", header)
+	fmt.Fprintln(w, header)
+	for i := 0; i < len(header); i++ {
+		fmt.Fprint(w, "-")
 	}
+	fmt.Fprintln(w)
+}
 
-	plugs := make(plugins.Plugins, 0, len(mm))
-	for _, p := range mm {
-		plugs = append(plugs, p)
+func printPlugins(w io.Writer, plugs []HelpPlugin) {
+	if len(plugs) == 0 {
+		return
 	}
 
-	sort.Slice(plugs, func(i, j int) bool {
-		return plugs[i].PluginName() < plugs[j].PluginName()
-	})
-
 	fmt.Fprintln(w, "\nUsing Plugins:")
 	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
 	fmt.Fprintf(tw, "\t%s\t%s\t%s\n", "Name", "Description", "Type")
 	fmt.Fprintf(tw, "\t%s\t%s\t%s\n", "----", "-----------", "----")
 	for _, p := range plugs {
-		fmt.Fprintf(tw, "\t%s\t%s\t%s\n", p.PluginName(), desc(p), typeName(p))
+		fmt.Fprintf(tw, "\t%s\t%s\t%s\n", p.Name, p.Description, p.Type)
 	}
 
 	tw.Flush()
-	return nil
 }
 
 func typeName(p plugins.Plugin) string {
+	p = unwrapPlugin(p)
+
 	rv := reflect.Indirect(reflect.ValueOf(p))
 	rt := reflect.TypeOf(rv.Interface())
 
@@ -139,12 +130,33 @@ func typeName(p plugins.Plugin) string {
 	return bb.String()
 }
 
+// scopedPlugins returns the plugin's scoped plugins,
+// sorted by name, with duplicate names removed.
+func scopedPlugins(p plugins.Plugin) (plugins.Plugins, error) {
+	mm := map[string]plugins.Plugin{}
+
+	if err := usingPlugins(p, mm); err != nil {
+		return nil, err
+	}
+
+	plugs := make(plugins.Plugins, 0, len(mm))
+	for _, p := range mm {
+		plugs = append(plugs, p)
+	}
+
+	sort.Slice(plugs, func(i, j int) bool {
+		return plugs[i].PluginName() < plugs[j].PluginName()
+	})
+
+	return plugs, nil
+}
+
 func usingPlugins(plug plugins.Plugin, mm map[string]plugins.Plugin) error {
 	if mm == nil {
 		return fmt.Errorf("mm cannot be nil")
 	}
 
-	wp, ok := plug.(plugins.Scoper)
+	wp, ok := plugins.As[plugins.Scoper](plug)
 	if !ok {
 		return nil
 	}
@@ -158,37 +170,65 @@ func usingPlugins(plug plugins.Plugin, mm map[string]plugins.Plugin) error {
 	return nil
 }
 
-func printCommands(w io.Writer, main plugins.Plugin) error {
-	sc, ok := main.(SubCommander)
-	if !ok {
-		return nil
+func printCommands(w io.Writer, cmds []HelpCommand) {
+	if len(cmds) == 0 {
+		return
 	}
 
-	plugs := sc.SubCommands()
-	if len(plugs) == 0 {
-		return nil
+	const ac = "\nAvailable Commands:\n"
+	fmt.Fprint(w, ac)
+
+	groups := groupCommands(cmds)
+	if len(groups) == 1 && len(groups[0].Title) == 0 {
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintf(tw, "\t%s\t%s\n", "Command", "Description")
+		fmt.Fprintf(tw, "\t%s\t%s\n", "-------", "-----------")
+		printCommandRows(tw, cmds)
+		tw.Flush()
+		return
 	}
 
-	sort.Slice(plugs, func(i, j int) bool {
-		return plugs[i].PluginName() < plugs[j].PluginName()
-	})
+	for _, g := range groups {
+		fmt.Fprintf(w, "\n%s:\n", g.Title)
 
-	const ac = "\nAvailable Commands:\n"
-	fmt.Fprint(w, ac)
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		printCommandRows(tw, g.items)
+		tw.Flush()
+	}
+}
 
-	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
-	fmt.Fprintf(tw, "\t%s\t%s\n", "Command", "Description")
-	fmt.Fprintf(tw, "\t%s\t%s\n", "-------", "-----------")
-	for _, c := range plugs {
-		line := fmt.Sprintf("\t%s", cmdName(c))
-		if d := desc(c); d != "" {
+func printCommandRows(w io.Writer, cmds []HelpCommand) {
+	for _, c := range cmds {
+		line := fmt.Sprintf("\t%s", c.Name)
+		if d := c.note(); d != "" {
 			line = fmt.Sprintf("%s\t%s", line, d)
 		}
-		fmt.Fprintln(tw, line)
+		fmt.Fprintln(w, line)
 	}
+}
 
-	tw.Flush()
-	return nil
+// note returns the description of the command, tagged
+// if it is experimental, and followed by its deprecation
+// notice, if any.
+func (c HelpCommand) note() string {
+	d := c.Description
+	if c.Experimental {
+		d = strings.TrimSpace("[experimental] " + d)
+	}
+	if len(c.Deprecated) > 0 {
+		d = strings.TrimSpace(fmt.Sprintf("%s (%s)", d, c.Deprecated))
+	}
+	return d
+}
+
+// cmdNote is HelpCommand.note for the command.
+func cmdNote(c Commander) string {
+	hc := HelpCommand{
+		Description:  desc(c),
+		Deprecated:   deprecation(c),
+		Experimental: experimental(c),
+	}
+	return hc.note()
 }
 
 func desc(p plugins.Plugin) string {
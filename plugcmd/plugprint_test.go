@@ -128,3 +128,51 @@ Using Plugins:
 
 	r.Equal(exp, act)
 }
+
+func Test_PrintError(t *testing.T) {
+	t.Parallel()
+	r := require.New(t)
+
+	bb := &bytes.Buffer{}
+	err := PrintError(bb, &NotFoundError{
+		Name:        "biuld",
+		Suggestions: []string{"build", "bench"},
+	})
+	r.NoError(err)
+
+	exp := `$ biuld
+-------
+Unknown command "biuld".
+
+Did you mean:
+  build
+  bench`
+
+	r.Equal(exp, strings.TrimSpace(bb.String()))
+
+	bb.Reset()
+	err = PrintError(bb, fmt.Errorf("wrapped: %w", &AmbiguousError{
+		Name: "b",
+		Candidates: []Commander{
+			cmd{name: "one", desc: "first"},
+			cmd{name: "two", desc: "second"},
+		},
+	}))
+	r.NoError(err)
+
+	exp = `$ b
+---
+Ambiguous command "b".
+
+Matching Commands:
+  Command  Description  Type
+  -------  -----------  ----
+  one      first        github.com/markbates/plugins/plugcmd.cmd
+  two      second       github.com/markbates/plugins/plugcmd.cmd`
+
+	r.Equal(exp, strings.TrimSpace(bb.String()))
+
+	bb.Reset()
+	r.NoError(PrintError(bb, io.EOF))
+	r.Equal("EOF\n", bb.String())
+}
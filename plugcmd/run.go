@@ -0,0 +1,447 @@
+package plugcmd
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/markbates/plugins"
+)
+
+// Kinds of RunError.
+var (
+	ErrNotFound = errors.New("command not found")
+	ErrUsage    = errors.New("usage error")
+	ErrFailed   = errors.New("command failed")
+)
+
+// RunError is returned by Run. Kind is one of ErrNotFound,
+// ErrUsage, or ErrFailed, and can be checked with errors.Is.
+type RunError struct {
+	Kind error    // ErrNotFound, ErrUsage, or ErrFailed
+	Path []string // names of the commands that were resolved
+	Err  error    // the underlying error
+}
+
+func (e *RunError) Error() string {
+	bb := &strings.Builder{}
+	if len(e.Path) > 0 {
+		fmt.Fprintf(bb, "%s: ", strings.Join(e.Path, " "))
+	}
+	switch {
+	case e.Err == nil:
+		fmt.Fprint(bb, e.Kind)
+	case errors.Is(e.Err, e.Kind):
+		fmt.Fprint(bb, e.Err)
+	default:
+		fmt.Fprintf(bb, "%s: %s", e.Kind, e.Err)
+	}
+	return bb.String()
+}
+
+func (e *RunError) Unwrap() []error {
+	return []error{e.Kind, e.Err}
+}
+
+// Run resolves the command named by args, walking the
+// SubCommander tree, and runs it. For `app a b c`, args
+// is `a b c`.
+//
+// At each level the command's Flagger flag set, if it has
+// one, is parsed, with Parse if it is a Shorthander. Flags
+// come before the name of the next sub-command. The
+// deepest Commander found has its Main called with every
+// argument after its name. If it is a Shorthander, its
+// flags have already been parsed, with GNU syntax, that
+// the standard flag package can't parse again, so Main
+// is called with only the positional arguments, set.Args.
+//
+// On `-h`, `-help`, or `--help`, the help for the command
+// at that level is printed to the collection's Stdout, with
+// PrintTerm, and nil is returned. It is only colored, or
+// paged, if a TermOptioner in the collection asks for it.
+// On an unknown command, or a usage error, the help is
+// printed to the collection's Stderr. Unknown commands are
+// reported with a *NotFoundError, including suggestions,
+// and names matching more than one command with an
+// *AmbiguousError.
+//
+// A command that is a Commander, and a SubCommander, has
+// its Main called with the args that don't name one of its
+// sub-commands, unless the first is close to the name of
+// one, a likely typo, that is reported as unknown.
+//
+// Main is wrapped by any BeforeRunner and AfterRunner
+// hooks in the collection, or scoped by the commands that
+// were resolved, in that order.
+//
+// If the command is an ArgsSpec, its positional arguments
+// are validated before Main is called, and a usage error is
+// returned if they don't match.
+//
+// Main can return an error wrapping ErrUsage to have the
+// help printed to Stderr, and the error reported as a usage
+// error.
+//
+// If the first of the args is a user alias, see
+// UserAliaser, and not a command, it is expanded with
+// ExpandAliases, and each of its steps is run in turn.
+// The help of each command lists the user aliases that
+// run it.
+//
+// The flags of each command are bound, see Bind, for its
+// path, from the environment, and then the config file
+// named by the collection's ConfigFiler, relative to
+// root. The help printed by Run reports their sources.
+// The bound values are passed to Main, ahead of the
+// args, as `-name=value`, so a command that parses its
+// args again, or a remote one, gets them too.
+//
+// If the command is a Deprecator, a warning is printed
+// to Stderr before it is run.
+//
+// The hidden CompleteCmd, `app __complete ...`, is used by
+// shell completion scripts and writes the candidates for the
+// rest of the args with Complete.
+func Run(ctx context.Context, root string, args []string, plugs plugins.Plugins) error {
+	return run(ctx, root, runRoot(plugs), args, plugs)
+}
+
+// run is Run, with the command tree starting at top.
+func run(ctx context.Context, root string, top plugins.Plugin, args []string, plugs plugins.Plugins) error {
+	if len(args) > 0 && args[0] == CompleteCmd {
+		return printCompletions(plugins.Stdout(plugs...), completions(args[1:], top))
+	}
+
+	steps, err := expandAliases(args, userAliases(plugs), top, nil)
+	if err != nil {
+		return &RunError{Kind: ErrUsage, Err: err}
+	}
+
+	cfg, err := runConfig(root, top, plugs)
+	if err != nil {
+		return &RunError{Kind: ErrUsage, Err: err}
+	}
+
+	for _, step := range steps {
+		if err := runArgs(ctx, root, top, step, plugs, cfg); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// runArgs resolves, and runs, the command
+// named by args, starting at top.
+func runArgs(ctx context.Context, root string, top plugins.Plugin, args []string, plugs plugins.Plugins, cfg Config) error {
+	stdout := plugins.Stdout(plugs...)
+	stderr := plugins.Stderr(plugs...)
+
+	aliases := userAliasList(plugs)
+
+	node := top
+	var path []string
+	var lf levelFlags
+	var chain []plugins.Plugin
+	if _, ok := top.(Commander); ok {
+		chain = append(chain, top)
+	}
+
+	// the names of the parents of node,
+	// for the usage synopsis
+	parents := func() PrintOptions {
+		opts := PrintOptions{
+			UserAliases: aliasesFor(aliases, path),
+			Flags:       lf.set,
+			Bindings:    lf.bindings,
+		}
+
+		if len(path) == 0 {
+			return opts
+		}
+
+		names := []string{cmdName(top)}
+		opts.Path = append(names, path[:len(path)-1]...)
+		return opts
+	}
+
+	for {
+		var rest []string
+		var err error
+		lf, rest, err = parseLevel(node, args, append([]string{cmdName(top)}, path...), cfg)
+		if errors.Is(err, flag.ErrHelp) {
+			return PrintTerm(stdout, node, runTermOptions(plugs, parents()))
+		}
+
+		if err != nil {
+			PrintWith(stderr, node, parents())
+			return &RunError{Kind: ErrUsage, Path: path, Err: err}
+		}
+
+		var lookup error
+		if sc, ok := node.(SubCommander); ok && len(rest) > 0 {
+			c, err := FindE(rest[0], commandPlugins(sc.SubCommands()))
+			if c != nil {
+				node = c
+				chain = append(chain, c)
+				path = append(path, cmdName(c))
+				args = rest[1:]
+				continue
+			}
+
+			var ae *AmbiguousError
+			if errors.As(err, &ae) {
+				PrintError(stderr, err)
+				return &RunError{Kind: ErrUsage, Path: path, Err: err}
+			}
+
+			// a mistyped sub-command is not
+			// an argument for the Commander
+			var nf *NotFoundError
+			if _, ok := node.(Commander); ok && errors.As(err, &nf) && isTypo(nf) {
+				PrintError(stderr, err)
+				fmt.Fprintln(stderr)
+				PrintWith(stderr, node, parents())
+				return &RunError{Kind: ErrNotFound, Path: path, Err: err}
+			}
+			lookup = err
+		}
+
+		cmd, ok := node.(Commander)
+		if !ok {
+			if len(rest) == 0 {
+				PrintWith(stderr, node, parents())
+				return &RunError{Kind: ErrUsage, Path: path, Err: errors.New("no command given")}
+			}
+
+			if lookup == nil {
+				lookup = &NotFoundError{Name: rest[0]}
+			}
+
+			PrintError(stderr, lookup)
+			fmt.Fprintln(stderr)
+			PrintWith(stderr, node, parents())
+			return &RunError{Kind: ErrNotFound, Path: path, Err: lookup}
+		}
+
+		if as, ok := plugins.As[ArgsSpec](cmd); ok {
+			if err := ValidateArgs(as.ArgsSpec(), rest); err != nil {
+				PrintWith(stderr, node, parents())
+				return &RunError{Kind: ErrUsage, Path: path, Err: err}
+			}
+		}
+
+		if d := deprecation(cmd); len(d) > 0 {
+			name := strings.Join(append([]string{cmdName(top)}, path...), " ")
+			fmt.Fprintf(stderr, "Warning: %q is %s\n", name, d)
+		}
+
+		margs := append(lf.boundArgs(), args...)
+		if _, ok := plugins.As[Shorthander](cmd); ok {
+			margs = rest
+		}
+
+		err = runHooked(ctx, root, cmd, margs, hookPlugins(plugs, chain))
+		if errors.Is(err, ErrUsage) {
+			PrintWith(stderr, node, parents())
+			return &RunError{Kind: ErrUsage, Path: path, Err: err}
+		}
+
+		if err != nil {
+			return &RunError{Kind: ErrFailed, Path: path, Err: err}
+		}
+
+		return nil
+	}
+}
+
+// hookPlugins returns the plugins in the collection,
+// followed by those scoped by each command in the chain,
+// without duplicate names.
+func hookPlugins(plugs plugins.Plugins, chain []plugins.Plugin) plugins.Plugins {
+	all := append(plugins.Plugins{}, plugs...)
+	for _, c := range chain {
+		if sc, ok := plugins.As[plugins.Scoper](c); ok {
+			all = append(all, sc.ScopedPlugins()...)
+		}
+	}
+
+	res := make(plugins.Plugins, 0, len(all))
+	seen := map[string]bool{}
+	for _, p := range all {
+		if p == nil || seen[p.PluginName()] {
+			continue
+		}
+		seen[p.PluginName()] = true
+		res = append(res, p)
+	}
+	return res
+}
+
+// runHooked calls cmd's Main, wrapped by the BeforeRunner
+// and AfterRunner hooks, in order. The first BeforeRunner
+// error stops Main from being called. Every AfterRunner is
+// called, with the error, and any errors they return are
+// joined to it.
+func runHooked(ctx context.Context, root string, cmd Commander, args []string, hooks plugins.Plugins) error {
+	var err error
+	for _, h := range plugins.ByType[BeforeRunner](hooks) {
+		if err = h.BeforeRun(ctx, root, cmd, args); err != nil {
+			break
+		}
+	}
+
+	if err == nil {
+		err = cmd.Main(ctx, root, args)
+	}
+
+	var errs []error
+	for _, h := range plugins.ByType[AfterRunner](hooks) {
+		if aerr := h.AfterRun(ctx, root, cmd, args, err); aerr != nil {
+			errs = append(errs, aerr)
+		}
+	}
+
+	if len(errs) == 0 {
+		return err
+	}
+
+	return errors.Join(append([]error{err}, errs...)...)
+}
+
+// levelFlags is the flag set parsed for a level of the
+// command tree, and where Bind found its values.
+type levelFlags struct {
+	set      *flag.FlagSet
+	bindings map[string]Binding
+}
+
+// boundArgs returns the flags that were bound, sorted
+// by name, as `-name=value` arguments.
+func (lf levelFlags) boundArgs() []string {
+	if lf.set == nil || len(lf.bindings) == 0 {
+		return nil
+	}
+
+	var args []string
+	lf.set.VisitAll(func(f *flag.Flag) {
+		if _, ok := lf.bindings[f.Name]; ok {
+			args = append(args, fmt.Sprintf("-%s=%s", f.Name, f.Value))
+		}
+	})
+	return args
+}
+
+// parseLevel parses the args for a single level of the
+// command tree, returning the args left over. The flags
+// of a Flagger are then bound, see Bind, for the command
+// at path, from the environment, and cfg.
+func parseLevel(p plugins.Plugin, args []string, path []string, cfg Config) (levelFlags, []string, error) {
+	if f, ok := p.(Flagger); ok {
+		set, err := f.Flags()
+		if err != nil {
+			return levelFlags{}, nil, err
+		}
+
+		lf := levelFlags{set: set}
+		rest, err := parseFlags(p, set, args)
+		if err != nil && !errors.Is(err, flag.ErrHelp) {
+			return lf, nil, err
+		}
+
+		// bound for the help, too
+		bindings, berr := Bind(set, path, cfg)
+		if berr != nil {
+			return lf, nil, berr
+		}
+		lf.bindings = bindings
+
+		return lf, rest, err
+	}
+
+	for _, a := range args {
+		if a == "--" || !strings.HasPrefix(a, "-") {
+			break
+		}
+
+		if isHelpFlag(a) {
+			return levelFlags{}, nil, flag.ErrHelp
+		}
+	}
+
+	if _, ok := p.(Commander); ok {
+		return levelFlags{}, args, nil
+	}
+
+	if len(args) > 0 && args[0] == "--" {
+		return levelFlags{}, args[1:], nil
+	}
+
+	if len(args) > 0 && strings.HasPrefix(args[0], "-") {
+		return levelFlags{}, nil, fmt.Errorf("flag provided but not defined: %s", args[0])
+	}
+
+	return levelFlags{}, args, nil
+}
+
+// parseFlags parses the args into the plugin's set,
+// with Parse if it is a Shorthander.
+func parseFlags(p plugins.Plugin, set *flag.FlagSet, args []string) ([]string, error) {
+	ow := set.Output()
+	set.SetOutput(io.Discard)
+	defer set.SetOutput(ow)
+
+	if _, ok := plugins.As[Shorthander](p); ok {
+		// positional args are only mixed with flags
+		// when there are no sub-commands to find
+		sc, ok := p.(SubCommander)
+		intersperse := !ok || len(sc.SubCommands()) == 0
+
+		if err := parseGNU(set, shorthands(p), args, intersperse); err != nil {
+			return nil, err
+		}
+		return set.Args(), nil
+	}
+
+	if err := set.Parse(args); err != nil {
+		return nil, err
+	}
+
+	return set.Args(), nil
+}
+
+func isHelpFlag(a string) bool {
+	switch a {
+	case "-h", "-help", "--help":
+		return true
+	}
+	return false
+}
+
+func commandPlugins(cmds []Commander) plugins.Plugins {
+	plugs := make(plugins.Plugins, 0, len(cmds))
+	for _, c := range cmds {
+		plugs = append(plugs, c)
+	}
+	return plugs
+}
+
+var _ SubCommander = runRoot(nil)
+
+// runRoot is the top of the command tree used by Run.
+// Its sub-commands are the Commanders in the collection.
+type runRoot plugins.Plugins
+
+func (r runRoot) PluginName() string {
+	return filepath.Base(os.Args[0])
+}
+
+func (r runRoot) SubCommands() []Commander {
+	return plugins.ByType[Commander](plugins.Plugins(r))
+}
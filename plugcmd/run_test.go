@@ -0,0 +1,315 @@
+package plugcmd
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/markbates/iox"
+	"github.com/markbates/plugins"
+	"github.com/markbates/plugins/plugtest"
+	"github.com/stretchr/testify/require"
+)
+
+type runCmd struct {
+	name  string
+	subs  []Commander
+	flags func() *flag.FlagSet
+	fn    func(args []string) error
+}
+
+func (c *runCmd) PluginName() string {
+	return "run/" + c.name
+}
+
+func (c *runCmd) CmdName() string {
+	return c.name
+}
+
+func (c *runCmd) Main(ctx context.Context, root string, args []string) error {
+	if c.fn == nil {
+		return nil
+	}
+	return c.fn(args)
+}
+
+type runSubCmd struct {
+	*runCmd
+}
+
+func (c runSubCmd) SubCommands() []Commander {
+	return c.subs
+}
+
+type runFlagCmd struct {
+	runSubCmd
+}
+
+func (c runFlagCmd) Flags() (*flag.FlagSet, error) {
+	return c.flags(), nil
+}
+
+func runTree(t *testing.T) (plugins.Plugins, *iox.Buffer, *[]string, *bool) {
+	t.Helper()
+
+	var called []string
+	var verbose bool
+
+	leaf := &runCmd{
+		name: "c",
+		fn: func(args []string) error {
+			called = append(called, "c "+strings.Join(args, " "))
+			if len(args) > 0 && args[0] == "fail" {
+				return errors.New("boom")
+			}
+			return nil
+		},
+	}
+
+	b := runFlagCmd{runSubCmd{&runCmd{
+		name: "b",
+		subs: []Commander{leaf},
+		flags: func() *flag.FlagSet {
+			set := flag.NewFlagSet("b", flag.ContinueOnError)
+			set.BoolVar(&verbose, "v", false, "verbose")
+			return set
+		},
+		fn: func(args []string) error {
+			called = append(called, "b "+strings.Join(args, " "))
+			return nil
+		},
+	}}}
+
+	a := runSubCmd{&runCmd{
+		name: "a",
+		subs: []Commander{b},
+	}}
+
+	bb := &iox.Buffer{}
+	plugs := plugins.Plugins{
+		a,
+		&plugtest.IO{IO: bb.IO()},
+	}
+
+	return plugs, bb, &called, &verbose
+}
+
+func Test_Run(t *testing.T) {
+	t.Parallel()
+	r := require.New(t)
+
+	plugs, _, called, verbose := runTree(t)
+
+	err := Run(context.Background(), "", []string{"a", "b", "-v", "c", "x", "-y"}, plugs)
+	r.NoError(err)
+	r.Equal([]string{"c x -y"}, *called)
+	r.True(*verbose)
+}
+
+func Test_Run_Intermediate(t *testing.T) {
+	t.Parallel()
+	r := require.New(t)
+
+	plugs, _, called, _ := runTree(t)
+
+	err := Run(context.Background(), "", []string{"a", "b", "-v", "unknown"}, plugs)
+	r.NoError(err)
+	r.Equal([]string{"b -v unknown"}, *called)
+}
+
+func Test_Run_Failed(t *testing.T) {
+	t.Parallel()
+	r := require.New(t)
+
+	plugs, _, _, _ := runTree(t)
+
+	err := Run(context.Background(), "", []string{"a", "b", "c", "fail"}, plugs)
+	r.Error(err)
+	r.ErrorIs(err, ErrFailed)
+	r.False(errors.Is(err, ErrUsage))
+	r.Equal("a b c: command failed: boom", err.Error())
+
+	var re *RunError
+	r.True(errors.As(err, &re))
+	r.Equal([]string{"a", "b", "c"}, re.Path)
+}
+
+func Test_Run_NotFound(t *testing.T) {
+	t.Parallel()
+	r := require.New(t)
+
+	plugs, bb, _, _ := runTree(t)
+
+	err := Run(context.Background(), "", []string{"nope"}, plugs)
+	r.ErrorIs(err, ErrNotFound)
+	r.Equal(`unknown command "nope"`, err.Error())
+	r.Contains(bb.Err.String(), "Available Commands:")
+	r.Contains(bb.Err.String(), "a")
+
+	// a is a Commander itself, so an unknown
+	// sub-command is passed to a's Main
+	err = Run(context.Background(), "", []string{"a", "nope"}, plugs)
+	r.NoError(err)
+
+	// unless it is a typo
+	bb.Err.Reset()
+	err = Run(context.Background(), "", []string{"a", "bb"}, plugs)
+	r.ErrorIs(err, ErrNotFound)
+	r.Equal(`a: unknown command "bb", did you mean "b"?`, err.Error())
+	r.Contains(bb.Err.String(), "Did you mean:\n  b\n")
+	r.Contains(bb.Err.String(), "$ a\n")
+}
+
+func Test_Run_Usage(t *testing.T) {
+	t.Parallel()
+	r := require.New(t)
+
+	plugs, bb, called, _ := runTree(t)
+
+	err := Run(context.Background(), "", []string{"a", "b", "-x"}, plugs)
+	r.ErrorIs(err, ErrUsage)
+	r.Contains(err.Error(), "flag provided but not defined: -x")
+	r.Contains(bb.Err.String(), "$ b")
+	r.Empty(*called)
+
+	err = Run(context.Background(), "", nil, plugs)
+	r.ErrorIs(err, ErrUsage)
+	r.Contains(err.Error(), "no command given")
+
+	err = Run(context.Background(), "", []string{"-x", "a"}, plugs)
+	r.ErrorIs(err, ErrUsage)
+}
+
+func Test_Run_Help(t *testing.T) {
+	t.Parallel()
+	r := require.New(t)
+
+	plugs, bb, called, _ := runTree(t)
+
+	r.NoError(Run(context.Background(), "", []string{"a", "b", "--help"}, plugs))
+	r.Contains(bb.Out.String(), "$ b")
+	r.Contains(bb.Out.String(), "-v\tverbose")
+
+	bb.Out.Reset()
+	r.NoError(Run(context.Background(), "", []string{"a", "-h"}, plugs))
+	r.Contains(bb.Out.String(), "$ a")
+
+	bb.Out.Reset()
+	r.NoError(Run(context.Background(), "", []string{"-h"}, plugs))
+	r.Contains(bb.Out.String(), "Available Commands:")
+	r.NotContains(bb.Out.String(), "runRoot")
+
+	r.Empty(*called)
+}
+
+type scopedRunCmd struct {
+	*runCmd
+	plugs plugins.Plugins
+}
+
+func (c scopedRunCmd) ScopedPlugins() plugins.Plugins {
+	return c.plugs
+}
+
+type hookPlugin struct {
+	name   string
+	log    *[]string
+	before error
+	after  error
+}
+
+func (h hookPlugin) PluginName() string {
+	return h.name
+}
+
+func (h hookPlugin) BeforeRun(ctx context.Context, root string, cmd Commander, args []string) error {
+	*h.log = append(*h.log, fmt.Sprintf("before %s %s %s %v", h.name, root, cmdName(cmd), args))
+	return h.before
+}
+
+func (h hookPlugin) AfterRun(ctx context.Context, root string, cmd Commander, args []string, err error) error {
+	*h.log = append(*h.log, fmt.Sprintf("after %s %v", h.name, err))
+	return h.after
+}
+
+func hookTree(log *[]string, fail error, hooks ...plugins.Plugin) plugins.Plugins {
+	leaf := scopedRunCmd{
+		runCmd: &runCmd{
+			name: "c",
+			fn: func(args []string) error {
+				*log = append(*log, "main")
+				return fail
+			},
+		},
+		plugs: plugins.Plugins{
+			hookPlugin{name: "scoped", log: log},
+			hooks[0], // duplicates are only called once
+		},
+	}
+
+	plugs := plugins.Plugins{
+		runSubCmd{&runCmd{name: "a", subs: []Commander{leaf}}},
+	}
+	return append(plugs, hooks...)
+}
+
+func Test_Run_Hooks(t *testing.T) {
+	t.Parallel()
+	r := require.New(t)
+
+	var log []string
+	plugs := hookTree(&log, nil, hookPlugin{name: "fed", log: &log})
+
+	r.NoError(Run(context.Background(), "root", []string{"a", "c", "x"}, plugs))
+
+	r.Equal([]string{
+		"before fed root c [x]",
+		"before scoped root c [x]",
+		"main",
+		"after fed <nil>",
+		"after scoped <nil>",
+	}, log)
+}
+
+func Test_Run_Hooks_Abort(t *testing.T) {
+	t.Parallel()
+	r := require.New(t)
+
+	var log []string
+	plugs := hookTree(&log, nil, hookPlugin{name: "auth", log: &log, before: errors.New("denied")})
+
+	err := Run(context.Background(), "root", []string{"a", "c"}, plugs)
+	r.ErrorIs(err, ErrFailed)
+	r.Equal("a c: command failed: denied", err.Error())
+
+	r.Equal([]string{
+		"before auth root c []",
+		"after auth denied",
+		"after scoped denied",
+	}, log)
+}
+
+func Test_Run_Hooks_Failed(t *testing.T) {
+	t.Parallel()
+	r := require.New(t)
+
+	var log []string
+	boom := errors.New("boom")
+	plugs := hookTree(&log, boom, hookPlugin{name: "stats", log: &log, after: errors.New("stats down")})
+
+	err := Run(context.Background(), "root", []string{"a", "c"}, plugs)
+	r.ErrorIs(err, ErrFailed)
+	r.ErrorIs(err, boom)
+	r.Contains(err.Error(), "stats down")
+
+	r.Equal([]string{
+		"before stats root c []",
+		"before scoped root c []",
+		"main",
+		"after stats boom",
+		"after scoped boom",
+	}, log)
+}
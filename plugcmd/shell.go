@@ -0,0 +1,441 @@
+package plugcmd
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"unicode"
+
+	"github.com/markbates/plugins"
+)
+
+var _ Commander = &Shell{}
+var _ Describer = &Shell{}
+var _ Namer = &Shell{}
+var _ plugins.FSSetable = &Shell{}
+var _ plugins.IOSetable = &Shell{}
+var _ plugins.Needer = &Shell{}
+
+// Shell is a Commander, `app shell`, that reads commands
+// from an interactive prompt, and runs them with Run,
+// against the same plugins, until the end of the session.
+//
+// The plugins are configured once, when the shell starts,
+// with the stdio and file system given to the Shell, if
+// any. Lines are split into args like a POSIX shell, see
+// SplitArgs, and kept in a history file under root.
+//
+// On a terminal, Tab completes the names of commands,
+// their aliases, and flags, like Complete, and the arrow
+// keys move through the line, and the history. Ctrl-C
+// cancels the running command, not the shell. Ctrl-D,
+// `exit`, or `quit` leave the shell, and `help [cmd...]`
+// prints the help of a command with Print.
+//
+// Under Execute, commands are found starting at the root
+// given to Execute, as they are for the program itself,
+// and the plugins default to those of Execute, otherwise
+// the commands are the Commanders in the plugins.
+type Shell struct {
+	// Name of the command. Defaults to "shell".
+	Name string
+
+	// Prompt to show. Defaults to the name of the
+	// application, followed by "> ".
+	Prompt string
+
+	// History file, relative to root. Defaults to
+	// ".<app>_history". Use "-" to keep no history.
+	History string
+
+	// Plugins to run commands from. If nil, the plugins
+	// given with WithPlugins, or by Execute, are used.
+	Plugins plugins.Plugins
+
+	feeder plugins.FeederFn
+	io     plugins.IO
+	ioSet  bool
+	fs     fs.FS
+	sigs   <-chan os.Signal
+}
+
+func (s *Shell) PluginName() string {
+	return "plugcmd/shell"
+}
+
+func (s *Shell) CmdName() string {
+	if len(s.Name) > 0 {
+		return s.Name
+	}
+	return "shell"
+}
+
+func (s *Shell) Description() string {
+	return "Start an interactive shell"
+}
+
+func (s *Shell) WithPlugins(fn plugins.FeederFn) error {
+	s.feeder = fn
+	return nil
+}
+
+func (s *Shell) SetStdio(oi plugins.IO) error {
+	s.io = oi
+	s.ioSet = true
+	return nil
+}
+
+func (s *Shell) SetFileSystem(fsys fs.FS) error {
+	s.fs = fsys
+	return nil
+}
+
+// shellMaxHistory is the number of lines
+// read back from the history file.
+const shellMaxHistory = 1000
+
+func (s *Shell) Main(ctx context.Context, root string, args []string) error {
+	if len(args) > 0 {
+		return fmt.Errorf("unexpected arguments: %s: %w", strings.Join(args, " "), ErrUsage)
+	}
+
+	plugs, err := s.plugins(ctx)
+	if err != nil {
+		return err
+	}
+
+	stdin := shellStdin(plugs)
+	if s.ioSet {
+		if err := plugs.SetStdio(s.io); err != nil {
+			return err
+		}
+		stdin = s.io.Stdin()
+		plugs = withStdio(plugs, s.io)
+	}
+
+	if s.fs != nil {
+		if err := plugs.SetFileSystem(s.fs); err != nil {
+			return err
+		}
+	}
+
+	stdout := plugins.Stdout(plugs...)
+	stderr := plugins.Stderr(plugs...)
+
+	var top plugins.Plugin = runRoot(plugs)
+	if ex, ok := executedFrom(ctx); ok {
+		top = ex.root
+	}
+	app := cmdName(top)
+
+	hist, err := loadHistory(s.historyPath(root, app))
+	if err != nil {
+		return err
+	}
+
+	prompt := s.Prompt
+	if len(prompt) == 0 {
+		prompt = app + "> "
+	}
+
+	release := claimInterrupts(ctx)
+	defer release()
+
+	sigs := s.sigs
+	if sigs == nil {
+		ch := make(chan os.Signal, 1)
+		signal.Notify(ch, os.Interrupt)
+		defer signal.Stop(ch)
+		sigs = ch
+	}
+
+	lr := newLineReader(stdin, stdout, hist, func(line string) []string {
+		return shellCompletions(top, line)
+	})
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		line, err := lr.ReadLine(prompt)
+		if errors.Is(err, errInterrupt) {
+			continue
+		}
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		args, err := SplitArgs(line)
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			continue
+		}
+
+		if len(args) == 0 {
+			continue
+		}
+
+		if err := hist.add(line); err != nil {
+			fmt.Fprintln(stderr, err)
+		}
+
+		switch args[0] {
+		case "exit", "quit":
+			return nil
+		case "help":
+			node, err := findPath(top, args[1:])
+			if err != nil {
+				PrintError(stderr, err)
+				continue
+			}
+			Print(stdout, node)
+			continue
+		}
+
+		printRunError(stderr, shellRun(ctx, root, top, args, plugs, sigs))
+	}
+}
+
+// shellRun runs the command, starting at top, canceling
+// its context on SIGINT, and waits for it to return.
+func shellRun(ctx context.Context, root string, top plugins.Plugin, args []string, plugs plugins.Plugins, sigs <-chan os.Signal) error {
+	// drop interrupts from the prompt
+	for len(sigs) > 0 {
+		<-sigs
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- run(ctx, root, top, args, plugs)
+	}()
+
+	for {
+		select {
+		case err := <-done:
+			return err
+		case <-sigs:
+			cancel()
+		}
+	}
+}
+
+// plugins returns the plugins to run,
+// without the shell itself.
+func (s *Shell) plugins(ctx context.Context) (plugins.Plugins, error) {
+	all := s.Plugins
+	if all == nil && s.feeder != nil {
+		all = s.feeder()
+	}
+
+	if ex, ok := executedFrom(ctx); ok && all == nil {
+		all = ex.plugs
+	}
+
+	if all == nil {
+		return nil, fmt.Errorf("no plugins given to %s", s.PluginName())
+	}
+
+	plugs := make(plugins.Plugins, 0, len(all))
+	for _, p := range all {
+		if p == nil || p.PluginName() == s.PluginName() {
+			continue
+		}
+		plugs = append(plugs, p)
+	}
+	return plugs, nil
+}
+
+func (s *Shell) historyPath(root string, app string) string {
+	switch s.History {
+	case "-":
+		return ""
+	case "":
+		return filepath.Join(root, "."+app+"_history")
+	}
+
+	if filepath.IsAbs(s.History) {
+		return s.History
+	}
+	return filepath.Join(root, s.History)
+}
+
+func shellStdin(plugs plugins.Plugins) io.Reader {
+	for _, p := range plugins.ByType[plugins.Stdiner](plugs) {
+		if r := p.Stdin(); r != nil {
+			return r
+		}
+	}
+	return os.Stdin
+}
+
+// shellCompletions returns the completions for the last
+// word of the line, including the shell's built-ins.
+func shellCompletions(top plugins.Plugin, line string) []string {
+	args, err := SplitArgs(line)
+	if err != nil {
+		return nil
+	}
+
+	if len(line) == 0 || unicode.IsSpace(rune(line[len(line)-1])) {
+		args = append(args, "")
+	}
+
+	var res []string
+	if len(args) == 1 {
+		for _, b := range []string{"exit", "help", "quit"} {
+			if strings.HasPrefix(b, args[0]) {
+				res = append(res, b)
+			}
+		}
+	}
+
+	if len(args) > 1 && args[0] == "help" {
+		args = args[1:]
+	}
+
+	for _, c := range completions(args, top) {
+		res = append(res, c.value)
+	}
+	return res
+}
+
+// history is the lines entered into the shell. Lines
+// are appended to the file, if there is one, as they
+// are added.
+type history struct {
+	path  string
+	lines []string
+}
+
+func loadHistory(path string) (*history, error) {
+	h := &history{path: path}
+	if len(path) == 0 {
+		return h, nil
+	}
+
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return h, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	scan := bufio.NewScanner(f)
+	for scan.Scan() {
+		if line := scan.Text(); len(line) > 0 {
+			h.lines = append(h.lines, line)
+		}
+	}
+
+	if len(h.lines) > shellMaxHistory {
+		h.lines = h.lines[len(h.lines)-shellMaxHistory:]
+	}
+
+	return h, scan.Err()
+}
+
+func (h *history) add(line string) error {
+	if n := len(h.lines); n > 0 && h.lines[n-1] == line {
+		return nil
+	}
+
+	h.lines = append(h.lines, line)
+
+	if len(h.path) == 0 {
+		return nil
+	}
+
+	f, err := os.OpenFile(h.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+
+	if _, err := fmt.Fprintln(f, line); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// SplitArgs splits the line into args, like a POSIX
+// shell. Args are separated by spaces, and can be quoted
+// with single, or double, quotes. Inside double quotes, and
+// outside of quotes, a backslash escapes the next
+// character.
+//
+//	SplitArgs(`build -tags "dev prod" 'it''s'`) // [build -tags dev prod its]
+func SplitArgs(line string) ([]string, error) {
+	var args []string
+	var cur strings.Builder
+	var inArg bool
+	var quote rune
+
+	rs := []rune(line)
+	for i := 0; i < len(rs); i++ {
+		r := rs[i]
+
+		switch {
+		case quote == '\'':
+			if r == '\'' {
+				quote = 0
+				continue
+			}
+			cur.WriteRune(r)
+		case quote == '"':
+			if r == '"' {
+				quote = 0
+				continue
+			}
+			if r == '\\' && i+1 < len(rs) && strings.ContainsRune("\"\\$`", rs[i+1]) {
+				i++
+				r = rs[i]
+			}
+			cur.WriteRune(r)
+		case r == '\\':
+			if i+1 == len(rs) {
+				return nil, fmt.Errorf("unterminated escape: %s", line)
+			}
+			i++
+			cur.WriteRune(rs[i])
+			inArg = true
+		case r == '\'' || r == '"':
+			quote = r
+			inArg = true
+		case unicode.IsSpace(r):
+			if inArg {
+				args = append(args, cur.String())
+				cur.Reset()
+				inArg = false
+			}
+		default:
+			cur.WriteRune(r)
+			inArg = true
+		}
+	}
+
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated %c quote: %s", quote, line)
+	}
+
+	if inArg {
+		args = append(args, cur.String())
+	}
+
+	return args, nil
+}
@@ -0,0 +1,280 @@
+package plugcmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// errInterrupt is returned by a lineReader
+// when Ctrl-C is pressed at the prompt.
+var errInterrupt = errors.New("interrupt")
+
+// lineReader reads the lines of the shell.
+type lineReader interface {
+	ReadLine(prompt string) (string, error)
+}
+
+// newLineReader returns an editor, when in and out are
+// terminals, or a reader of plain lines.
+func newLineReader(in io.Reader, out io.Writer, hist *history, complete func(string) []string) lineReader {
+	f, ok := in.(*os.File)
+	if ok && f != nil && isTerminal(f) {
+		if _, tty := terminal(out); tty {
+			// only edit lines if raw mode works here
+			if restore, err := makeRaw(f); err == nil {
+				restore()
+				return &editor{
+					in:       bufio.NewReader(f),
+					out:      out,
+					tty:      f,
+					hist:     hist,
+					complete: complete,
+				}
+			}
+		}
+	}
+
+	return &plainReader{
+		in:  bufio.NewReader(in),
+		out: out,
+	}
+}
+
+// plainReader reads lines, without editing, such
+// as from a pipe.
+type plainReader struct {
+	in  *bufio.Reader
+	out io.Writer
+}
+
+func (p *plainReader) ReadLine(prompt string) (string, error) {
+	fmt.Fprint(p.out, prompt)
+
+	line, err := p.in.ReadString('\n')
+	if errors.Is(err, io.EOF) && len(line) > 0 {
+		err = nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// editor reads lines from a terminal in raw mode, with
+// emacs style keys, history, and Tab completion.
+type editor struct {
+	in       *bufio.Reader
+	out      io.Writer
+	tty      *os.File // put into raw mode, if not nil
+	hist     *history
+	complete func(line string) []string
+
+	buf []rune
+	pos int
+}
+
+func ctrl(r rune) rune {
+	return r & 0x1f
+}
+
+func (e *editor) ReadLine(prompt string) (string, error) {
+	if e.tty != nil {
+		restore, err := makeRaw(e.tty)
+		if err != nil {
+			return "", err
+		}
+		defer restore()
+	}
+
+	e.buf = e.buf[:0]
+	e.pos = 0
+
+	// the history entry being shown, and the
+	// line being edited before moving to it
+	hi := len(e.hist.lines)
+	var saved []rune
+
+	show := func(i int) {
+		if hi == len(e.hist.lines) {
+			saved = append([]rune{}, e.buf...)
+		}
+		hi = i
+		if hi == len(e.hist.lines) {
+			e.buf = append([]rune{}, saved...)
+		} else {
+			e.buf = []rune(e.hist.lines[hi])
+		}
+		e.pos = len(e.buf)
+	}
+
+	e.refresh(prompt)
+
+	for {
+		r, _, err := e.in.ReadRune()
+		if err != nil {
+			fmt.Fprint(e.out, "\r\n")
+			return "", err
+		}
+
+		switch r {
+		case '\r', '\n':
+			fmt.Fprint(e.out, "\r\n")
+			return string(e.buf), nil
+		case ctrl('C'):
+			fmt.Fprint(e.out, "^C\r\n")
+			return "", errInterrupt
+		case ctrl('D'):
+			if len(e.buf) == 0 {
+				fmt.Fprint(e.out, "\r\n")
+				return "", io.EOF
+			}
+			e.deleteAt(e.pos)
+		case 127, ctrl('H'):
+			if e.pos > 0 {
+				e.pos--
+				e.deleteAt(e.pos)
+			}
+		case ctrl('A'):
+			e.pos = 0
+		case ctrl('E'):
+			e.pos = len(e.buf)
+		case ctrl('B'):
+			e.pos = max(e.pos-1, 0)
+		case ctrl('F'):
+			e.pos = min(e.pos+1, len(e.buf))
+		case ctrl('K'):
+			e.buf = e.buf[:e.pos]
+		case ctrl('U'):
+			e.buf = append([]rune{}, e.buf[e.pos:]...)
+			e.pos = 0
+		case ctrl('P'):
+			if hi > 0 {
+				show(hi - 1)
+			}
+		case ctrl('N'):
+			if hi < len(e.hist.lines) {
+				show(hi + 1)
+			}
+		case '\t':
+			e.tab()
+		case 27:
+			switch e.escape() {
+			case 'A':
+				if hi > 0 {
+					show(hi - 1)
+				}
+			case 'B':
+				if hi < len(e.hist.lines) {
+					show(hi + 1)
+				}
+			case 'C':
+				e.pos = min(e.pos+1, len(e.buf))
+			case 'D':
+				e.pos = max(e.pos-1, 0)
+			case 'H':
+				e.pos = 0
+			case 'F':
+				e.pos = len(e.buf)
+			case '~':
+				e.deleteAt(e.pos)
+			}
+		default:
+			if unicode.IsPrint(r) {
+				e.insert(string(r))
+			}
+		}
+
+		e.refresh(prompt)
+	}
+}
+
+// escape reads the rest of an escape sequence, and
+// returns its final byte. The delete key, `ESC [ 3 ~`,
+// is returned as '~'.
+func (e *editor) escape() rune {
+	r, _, err := e.in.ReadRune()
+	if err != nil || (r != '[' && r != 'O') {
+		return 0
+	}
+
+	for {
+		r, _, err = e.in.ReadRune()
+		if err != nil {
+			return 0
+		}
+		if (r < '0' || r > '9') && r != ';' {
+			return r
+		}
+	}
+}
+
+func (e *editor) insert(s string) {
+	rs := []rune(s)
+	e.buf = append(e.buf[:e.pos], append(rs, e.buf[e.pos:]...)...)
+	e.pos += len(rs)
+}
+
+func (e *editor) deleteAt(i int) {
+	if i < len(e.buf) {
+		e.buf = append(e.buf[:i], e.buf[i+1:]...)
+	}
+}
+
+func (e *editor) refresh(prompt string) {
+	fmt.Fprintf(e.out, "\r%s%s\x1b[K", prompt, string(e.buf))
+	if n := len(e.buf) - e.pos; n > 0 {
+		fmt.Fprintf(e.out, "\x1b[%dD", n)
+	}
+}
+
+// tab completes the word before the cursor, as far as
+// the candidates agree, or lists them.
+func (e *editor) tab() {
+	if e.complete == nil {
+		return
+	}
+
+	line := string(e.buf[:e.pos])
+	cands := e.complete(line)
+	if len(cands) == 0 {
+		return
+	}
+
+	word := line[strings.LastIndexFunc(line, unicode.IsSpace)+1:]
+
+	prefix := commonPrefix(cands)
+	if len(cands) == 1 {
+		prefix += " "
+	}
+
+	if len(prefix) > len(word) && strings.HasPrefix(prefix, word) {
+		e.insert(prefix[len(word):])
+		return
+	}
+
+	if len(cands) > 1 {
+		fmt.Fprintf(e.out, "\r\n%s\r\n", strings.Join(cands, "  "))
+	}
+}
+
+func commonPrefix(ss []string) string {
+	if len(ss) == 0 {
+		return ""
+	}
+
+	prefix := ss[0]
+	for _, s := range ss[1:] {
+		for !strings.HasPrefix(s, prefix) {
+			_, n := utf8.DecodeLastRuneInString(prefix)
+			prefix = prefix[:len(prefix)-n]
+		}
+	}
+	return prefix
+}
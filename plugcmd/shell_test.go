@@ -0,0 +1,273 @@
+package plugcmd
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/markbates/iox"
+	"github.com/markbates/plugins"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_SplitArgs(t *testing.T) {
+	t.Parallel()
+
+	table := []struct {
+		in  string
+		exp []string
+		err bool
+	}{
+		{in: "", exp: nil},
+		{in: "  a  b\tc ", exp: []string{"a", "b", "c"}},
+		{in: `build -tags "dev prod"`, exp: []string{"build", "-tags", "dev prod"}},
+		{in: `'it''s' "a\"b" 'a\b'`, exp: []string{"its", `a"b`, `a\b`}},
+		{in: `a\ b "" x`, exp: []string{"a b", "", "x"}},
+		{in: `"a\nb"`, exp: []string{`a\nb`}},
+		{in: `"open`, err: true},
+		{in: `'open`, err: true},
+		{in: `a\`, err: true},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.in, func(t *testing.T) {
+			r := require.New(t)
+
+			args, err := SplitArgs(tt.in)
+			if tt.err {
+				r.Error(err)
+				return
+			}
+			r.NoError(err)
+			r.Equal(tt.exp, args)
+		})
+	}
+}
+
+// countingPlugin counts the times it is configured.
+type countingPlugin struct {
+	stdio int
+	fs    int
+}
+
+func (c *countingPlugin) PluginName() string {
+	return "counting"
+}
+
+func (c *countingPlugin) SetStdio(oi plugins.IO) error {
+	c.stdio++
+	return nil
+}
+
+func (c *countingPlugin) SetFileSystem(fsys fs.FS) error {
+	c.fs++
+	return nil
+}
+
+func Test_Shell(t *testing.T) {
+	t.Parallel()
+	r := require.New(t)
+
+	plugs, _, called, _ := runTree(t)
+
+	counter := &countingPlugin{}
+	sh := &Shell{}
+	plugs = append(plugs, counter, sh)
+	r.NoError(sh.WithPlugins(plugs.PluginFeeder()))
+
+	bb := &iox.Buffer{}
+	bb.In = strings.NewReader(strings.Join([]string{
+		`a b -v c "x y"`,
+		"",
+		"help a",
+		"bogus",
+		`a c "open`,
+		"a b c again",
+		"exit",
+		"a b c never",
+	}, "\n"))
+
+	r.NoError(sh.SetStdio(bb.IO()))
+	r.NoError(sh.SetFileSystem(fstest.MapFS{}))
+
+	root := t.TempDir()
+	r.NoError(sh.Main(context.Background(), root, nil))
+
+	r.Equal([]string{"c x y", "c again"}, *called)
+
+	r.Equal(1, counter.stdio)
+	r.Equal(1, counter.fs)
+
+	r.Contains(bb.Out.String(), "> ")
+	r.Contains(bb.Out.String(), "$ a")
+	r.Contains(bb.Err.String(), `Unknown command "bogus".`)
+	r.Contains(bb.Err.String(), "unterminated \" quote")
+
+	app := cmdName(runRoot(plugs))
+	b, err := os.ReadFile(filepath.Join(root, "."+app+"_history"))
+	r.NoError(err)
+	r.Equal("a b -v c \"x y\"\nhelp a\nbogus\na b c again\nexit\n", string(b))
+}
+
+func Test_Shell_Interrupt(t *testing.T) {
+	t.Parallel()
+	r := require.New(t)
+
+	started := make(chan struct{})
+	var ran []string
+
+	wait := &runCmd{name: "wait"}
+	wait.fn = func(args []string) error {
+		ran = append(ran, "wait")
+		close(started)
+		return nil
+	}
+
+	// runCmd doesn't see the context, so wait
+	// for it to be canceled here
+	blocking := Wrap(wait, func(cmd Commander, next CommanderFn) CommanderFn {
+		return func(ctx context.Context, root string, args []string) error {
+			if err := next(ctx, root, args); err != nil {
+				return err
+			}
+			<-ctx.Done()
+			return ctx.Err()
+		}
+	})
+
+	after := &runCmd{name: "after", fn: func(args []string) error {
+		ran = append(ran, "after")
+		return nil
+	}}
+
+	sigs := make(chan os.Signal, 1)
+	go func() {
+		<-started
+		sigs <- os.Interrupt
+	}()
+
+	bb := &iox.Buffer{}
+	bb.In = strings.NewReader("wait\nafter\n")
+
+	sh := &Shell{
+		History: "-",
+		Plugins: plugins.Plugins{blocking, after},
+		sigs:    sigs,
+	}
+	r.NoError(sh.SetStdio(bb.IO()))
+
+	ctx := context.Background()
+	r.NoError(sh.Main(ctx, t.TempDir(), nil))
+
+	r.Equal([]string{"wait", "after"}, ran)
+	r.Contains(bb.Err.String(), "context canceled")
+}
+
+func Test_Shell_Execute(t *testing.T) {
+	t.Parallel()
+	r := require.New(t)
+
+	var called []string
+	bar := &runCmd{name: "bar", fn: func(args []string) error {
+		called = append(called, "bar "+strings.Join(args, " "))
+		return nil
+	}}
+
+	sh := &Shell{History: "-"}
+	root := runSubCmd{&runCmd{
+		name: "app",
+		subs: []Commander{
+			runSubCmd{&runCmd{name: "foo", subs: []Commander{bar}}},
+			sh,
+		},
+	}}
+
+	bb := &iox.Buffer{}
+	bb.In = strings.NewReader("foo bar x\nhelp foo\nexit\n")
+
+	code := execute(context.Background(), root, []string{"shell"}, nil, bb.IO(), nil)
+	r.Equal(ExitOK, code)
+
+	r.Equal([]string{"bar x"}, called)
+	r.Contains(bb.Out.String(), "app> ")
+	r.Contains(bb.Out.String(), "$ foo")
+	r.Contains(bb.Out.String(), "  bar\n")
+	r.Empty(bb.Err.String())
+}
+
+func Test_Shell_Completions(t *testing.T) {
+	t.Parallel()
+	r := require.New(t)
+
+	plugs, _, _, _ := runTree(t)
+	top := runRoot(plugs)
+
+	r.Equal([]string{"exit", "help", "quit", "a"}, shellCompletions(top, ""))
+	r.Equal([]string{"help"}, shellCompletions(top, "he"))
+	r.Equal([]string{"b"}, shellCompletions(top, "a "))
+	r.Equal([]string{"b"}, shellCompletions(top, "help a "))
+	r.Equal([]string{"-v"}, shellCompletions(top, "a b -"))
+	r.Empty(shellCompletions(top, `a "b`))
+}
+
+func Test_Shell_Editor(t *testing.T) {
+	t.Parallel()
+
+	table := []struct {
+		name string
+		in   string
+		exp  string
+		err  error
+	}{
+		{name: "line", in: "abc\r", exp: "abc"},
+		{name: "edit", in: "ac\x1b[Db\x1b[C!\r", exp: "abc!"},
+		{name: "backspace", in: "abx\x7fc\r", exp: "abc"},
+		{name: "home end", in: "bc\x01a\x05d\r", exp: "abcd"},
+		{name: "kill", in: "abc\x01\x0bxyz\r", exp: "xyz"},
+		{name: "delete", in: "xabc\x01\x1b[3~\r", exp: "abc"},
+		{name: "history", in: "\x1b[A\x1b[A\r", exp: "one"},
+		{name: "history back", in: "new\x1b[A\x1b[B\r", exp: "new"},
+		{name: "complete", in: "bu\t\r", exp: "build "},
+		{name: "complete common", in: "t\t\r", exp: "te"},
+		{name: "interrupt", in: "abc\x03", err: errInterrupt},
+		{name: "eof", in: "\x04", err: io.EOF},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			r := require.New(t)
+
+			bb := &iox.Buffer{}
+			bb.In = strings.NewReader(tt.in)
+
+			e := &editor{
+				in:   bufio.NewReader(bb.In),
+				out:  &bb.Out,
+				hist: &history{lines: []string{"one", "two"}},
+				complete: func(line string) []string {
+					var res []string
+					for _, c := range []string{"build", "test", "tee"} {
+						if strings.HasPrefix(c, line) {
+							res = append(res, c)
+						}
+					}
+					return res
+				},
+			}
+
+			line, err := e.ReadLine("> ")
+			if tt.err != nil {
+				r.ErrorIs(err, tt.err)
+				return
+			}
+			r.NoError(err)
+			r.Equal(tt.exp, line)
+		})
+	}
+}
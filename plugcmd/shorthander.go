@@ -0,0 +1,31 @@
+package plugcmd
+
+import (
+	"fmt"
+
+	"github.com/markbates/plugins"
+)
+
+// Shorthander can be implemented by a Flagger to give its
+// flags single letter short forms, for use with Parse. The
+// map is keyed by the letter, and the values are the long
+// names of the flags.
+//
+//	map[rune]string{'v': "verbose"} // -v, --verbose
+type Shorthander interface {
+	plugins.Plugin
+	Shorthands() map[rune]string
+}
+
+var _ Shorthander = ShorthanderFn(nil)
+
+// ShorthanderFn is a function that can be used to implement the Shorthander interface
+type ShorthanderFn func() map[rune]string
+
+func (fn ShorthanderFn) Shorthands() map[rune]string {
+	return fn()
+}
+
+func (fn ShorthanderFn) PluginName() string {
+	return fmt.Sprintf("%T", fn)
+}
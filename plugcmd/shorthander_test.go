@@ -0,0 +1,21 @@
+package plugcmd
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ShorthanderFn(t *testing.T) {
+	t.Parallel()
+	r := require.New(t)
+
+	exp := map[rune]string{'v': "verbose"}
+	fn := ShorthanderFn(func() map[rune]string {
+		return exp
+	})
+
+	r.Equal(exp, fn.Shorthands())
+	r.Equal(fmt.Sprintf("%T", fn), fn.PluginName())
+}
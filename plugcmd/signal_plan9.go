@@ -0,0 +1,16 @@
+//go:build plan9
+
+package plugcmd
+
+import "os"
+
+// shutdownSignals are the signals Execute cancels
+// the running command on.
+var shutdownSignals = []os.Signal{os.Interrupt}
+
+// signalCode is the exit status for a command stopped
+// by the signal. Notes have no numbers on Plan 9, so
+// it is always the status of an interrupt, SIGINT.
+func signalCode(sig os.Signal) int {
+	return 128 + 2
+}
@@ -0,0 +1,21 @@
+//go:build !plan9
+
+package plugcmd
+
+import (
+	"os"
+	"syscall"
+)
+
+// shutdownSignals are the signals Execute cancels
+// the running command on.
+var shutdownSignals = []os.Signal{os.Interrupt, syscall.SIGTERM}
+
+// signalCode is the exit status for a
+// command stopped by the signal.
+func signalCode(sig os.Signal) int {
+	if s, ok := sig.(syscall.Signal); ok {
+		return 128 + int(s)
+	}
+	return 128 + int(syscall.SIGINT)
+}
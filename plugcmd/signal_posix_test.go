@@ -0,0 +1,20 @@
+//go:build !plan9
+
+package plugcmd
+
+import (
+	"os"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_signalCode(t *testing.T) {
+	t.Parallel()
+	r := require.New(t)
+
+	r.Equal(130, signalCode(os.Interrupt))
+	r.Equal(143, signalCode(syscall.SIGTERM))
+	r.Contains(shutdownSignals, os.Signal(syscall.SIGTERM))
+}
@@ -0,0 +1,117 @@
+package plugcmd
+
+import (
+	"path"
+	"sort"
+	"strings"
+)
+
+// suggest returns the names, aliases, and plugin base
+// names of the commands that are close to name, ranked
+// by edit distance. Hidden commands are not suggested.
+func suggest(name string, cmds []Commander) []string {
+	if len(name) == 0 {
+		return nil
+	}
+
+	limit := typoLimit(name)
+
+	dists := map[string]int{}
+	add := func(s string) {
+		if len(s) == 0 || s == name {
+			return
+		}
+
+		d := distance(name, s)
+		if d > limit && !strings.HasPrefix(s, name) {
+			return
+		}
+
+		if cur, ok := dists[s]; !ok || d < cur {
+			dists[s] = d
+		}
+	}
+
+	for _, c := range visibleCommands(cmds) {
+		if n, ok := c.(Namer); ok {
+			add(n.CmdName())
+		}
+
+		if a, ok := c.(Aliaser); ok {
+			for _, s := range a.CmdAliases() {
+				add(s)
+			}
+		}
+
+		add(path.Base(c.PluginName()))
+	}
+
+	res := make([]string, 0, len(dists))
+	for s := range dists {
+		res = append(res, s)
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		if dists[res[i]] != dists[res[j]] {
+			return dists[res[i]] < dists[res[j]]
+		}
+		return res[i] < res[j]
+	})
+
+	return res
+}
+
+// typoLimit is the largest distance from
+// name of the names suggested for it.
+func typoLimit(name string) int {
+	return len([]rune(name))/3 + 1
+}
+
+// isTypo reports if the name, that was not found, is
+// within typoLimit of a suggestion, and not only the
+// prefix of one.
+func isTypo(nf *NotFoundError) bool {
+	if len(nf.Suggestions) == 0 {
+		return false
+	}
+	return distance(nf.Name, nf.Suggestions[0]) <= typoLimit(nf.Name)
+}
+
+// distance is the optimal string alignment distance
+// between a and b, the number of insertions, deletions,
+// substitutions, and adjacent transpositions needed to
+// turn a into b.
+func distance(a string, b string) int {
+	ra := []rune(a)
+	rb := []rune(b)
+
+	d := make([][]int, len(ra)+1)
+	for i := range d {
+		d[i] = make([]int, len(rb)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+
+			d[i][j] = min(
+				d[i-1][j]+1,
+				d[i][j-1]+1,
+				d[i-1][j-1]+cost,
+			)
+
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				d[i][j] = min(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+
+	return d[len(ra)][len(rb)]
+}
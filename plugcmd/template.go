@@ -0,0 +1,227 @@
+package plugcmd
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"text/template"
+
+	"github.com/markbates/plugins"
+)
+
+// DefaultHelpTemplate is the `text/template` used by Print.
+// It is executed with a *HelpModel and uses HelpFuncs.
+const DefaultHelpTemplate = `{{header .Name}}
+{{with .Type}}{{.}}
+{{end}}{{with .Synopsis}}
+Usage: {{.}}
+{{end}}{{with .Usage}}
+{{wrap .}}
+{{end}}{{with .Aliases}}
+{{section "Aliases"}}
+{{join . ", "}}
+{{end}}{{with .UserAliases}}
+{{section "User Aliases"}}
+{{aliases .}}{{end}}{{with .FlagUsage}}
+{{.}}{{end}}{{with .BoundFlags}}
+{{section "Flag Sources"}}
+{{sources .}}{{end}}{{with .Commands}}
+{{section "Available Commands"}}
+{{commands .}}{{end}}{{with .Plugins}}
+{{section "Using Plugins"}}
+{{plugins .}}{{end}}`
+
+// PrintOptions control how PrintWith renders help. The
+// zero value renders the same help as Print.
+type PrintOptions struct {
+	// Template to render the *HelpModel with. If nil, the
+	// plugin's HelpTemplater is used, if it has one,
+	// otherwise DefaultHelpTemplate is used. Templates
+	// should include HelpFuncs.
+	Template *template.Template
+
+	// Path is the names of the parent commands, used
+	// in the usage synopsis, such as `app` for `app build`.
+	Path []string
+
+	// UserAliases to list, along with any from the
+	// plugin's scoped plugins.
+	UserAliases []UserAlias
+
+	// Flags is the plugin's parsed flag set, to describe
+	// in place of a new one from its Flagger, and Bindings
+	// are where Bind found the values of its flags. Run
+	// sets both, so the help shows the values, and their
+	// sources, of the flags given.
+	Flags    *flag.FlagSet
+	Bindings map[string]Binding
+
+	HideType        bool // hide the Go type of the plugin
+	HideSynopsis    bool // hide the ArgsSpec usage synopsis
+	HideUsage       bool // hide the UsagePrinter output
+	HideAliases     bool // hide the Aliaser aliases
+	HideUserAliases bool // hide the user aliases
+	HideFlags       bool // hide the flags
+	HideCommands    bool // hide the Available Commands
+	HidePlugins     bool // hide the Using Plugins
+}
+
+// HelpFuncs returns the functions used by
+// DefaultHelpTemplate.
+//
+//	header    "$ name" underlined with dashes
+//	section   a section title, followed by a colon
+//	wrap      wraps text to the terminal width
+//	join      strings.Join
+//	aliases   a table of []HelpAlias
+//	commands  a table of []HelpCommand
+//	plugins   a table of []HelpPlugin
+//	sources   a table of []HelpFlag, and their sources
+//
+// When rendering with PrintTerm, the functions wrap
+// and color their output for the terminal.
+func HelpFuncs() template.FuncMap {
+	return helpFuncs(nil)
+}
+
+func helpFuncs(ts *termStyle) template.FuncMap {
+	if ts != nil {
+		return ts.funcs()
+	}
+
+	return template.FuncMap{
+		"header": func(name string) string {
+			bb := &strings.Builder{}
+			printHeader(bb, name)
+			return strings.TrimSuffix(bb.String(), "\n")
+		},
+		"section": func(title string) string {
+			return title + ":"
+		},
+		"wrap": func(s string) string {
+			return s
+		},
+		"join": strings.Join,
+		"aliases": func(aliases []HelpAlias) string {
+			return tableString(aliasRows(aliases))
+		},
+		"commands": func(cmds []HelpCommand) string {
+			bb := &strings.Builder{}
+			printCommands(bb, cmds)
+			return strings.TrimPrefix(bb.String(), "\nAvailable Commands:\n")
+		},
+		"plugins": func(plugs []HelpPlugin) string {
+			bb := &strings.Builder{}
+			printPlugins(bb, plugs)
+			return strings.TrimPrefix(bb.String(), "\nUsing Plugins:\n")
+		},
+		"sources": func(flags []HelpFlag) string {
+			return tableString(sourceRows(flags))
+		},
+	}
+}
+
+// PrintWith renders the help for the plugin, as
+// described by Describe, with a `text/template`.
+// Sections can be hidden with the PrintOptions.
+func PrintWith(w io.Writer, main plugins.Plugin, opts PrintOptions) error {
+	return printWith(w, main, opts, nil)
+}
+
+func printWith(w io.Writer, main plugins.Plugin, opts PrintOptions, ts *termStyle) error {
+	m, err := describe(main, opts)
+	if err != nil {
+		return err
+	}
+
+	if len(opts.Path) > 0 && len(m.Synopsis) > 0 {
+		m.Synopsis = strings.Join(opts.Path, " ") + " " + m.Synopsis
+	}
+
+	if opts.HideType {
+		m.Type = ""
+	}
+	if opts.HideSynopsis {
+		m.Synopsis = ""
+	}
+	if opts.HideUsage {
+		m.Usage = ""
+	}
+	m.addUserAliases(opts.UserAliases)
+
+	if opts.HideAliases {
+		m.Aliases = nil
+	}
+	if opts.HideUserAliases {
+		m.UserAliases = nil
+	}
+	if opts.HideFlags {
+		m.FlagUsage = ""
+		m.Flags = nil
+	}
+	if opts.HideCommands {
+		m.Commands = nil
+	}
+	if opts.HidePlugins {
+		m.Plugins = nil
+	}
+
+	t := opts.Template
+	switch {
+	case t == nil:
+		text := DefaultHelpTemplate
+		if ht, ok := plugins.As[HelpTemplater](main); ok {
+			text = ht.HelpTemplate()
+		}
+
+		t, err = template.New(cmdName(main)).Funcs(helpFuncs(ts)).Parse(text)
+		if err != nil {
+			return err
+		}
+	case ts != nil:
+		// swap in the terminal aware functions
+		t, err = t.Clone()
+		if err != nil {
+			return err
+		}
+		t = t.Funcs(helpFuncs(ts))
+	}
+
+	return t.Execute(w, m)
+}
+
+// tableString renders the rows in aligned
+// columns, like the other tables of Print.
+func tableString(rows [][]string) string {
+	bb := &strings.Builder{}
+	tw := tabwriter.NewWriter(bb, 0, 0, 2, ' ', 0)
+	for _, row := range rows {
+		fmt.Fprintf(tw, "\t%s\n", strings.Join(row, "\t"))
+	}
+	tw.Flush()
+	return bb.String()
+}
+
+func aliasRows(aliases []HelpAlias) [][]string {
+	rows := [][]string{
+		{"Alias", "Command"},
+		{"-----", "-------"},
+	}
+	for _, a := range aliases {
+		rows = append(rows, []string{a.Name, a.Command})
+	}
+	return rows
+}
+
+func sourceRows(flags []HelpFlag) [][]string {
+	rows := [][]string{
+		{"Flag", "Value", "Source"},
+		{"----", "-----", "------"},
+	}
+	for _, f := range flags {
+		rows = append(rows, []string{"-" + f.Name, f.Value, strings.TrimSpace(fmt.Sprintf("%s %s", f.Source, f.From))})
+	}
+	return rows
+}
@@ -0,0 +1,133 @@
+package plugcmd
+
+import (
+	"bytes"
+	"testing"
+	"text/template"
+
+	"github.com/markbates/plugins"
+	"github.com/stretchr/testify/require"
+)
+
+type templateCmd struct {
+	cmd
+	tmpl string
+}
+
+func (c templateCmd) HelpTemplate() string {
+	return c.tmpl
+}
+
+func Test_PrintWith_Default(t *testing.T) {
+	t.Parallel()
+	r := require.New(t)
+
+	c := cmd{
+		name: "main",
+		plugs: plugins.Plugins{
+			stringPlugin("one"),
+		},
+	}
+
+	exp := &bytes.Buffer{}
+	r.NoError(Print(exp, c))
+
+	act := &bytes.Buffer{}
+	r.NoError(PrintWith(act, c, PrintOptions{}))
+	r.Equal(exp.String(), act.String())
+
+	tmpl := template.Must(template.New("").Funcs(HelpFuncs()).Parse(DefaultHelpTemplate))
+
+	act.Reset()
+	r.NoError(PrintWith(act, c, PrintOptions{Template: tmpl}))
+	r.Equal(exp.String(), act.String())
+}
+
+func Test_PrintWith_Hide(t *testing.T) {
+	t.Parallel()
+	r := require.New(t)
+
+	c := cmd{
+		name: "main",
+		plugs: plugins.Plugins{
+			stringPlugin("one"),
+		},
+	}
+
+	bb := &bytes.Buffer{}
+	err := PrintWith(bb, c, PrintOptions{
+		HideType:     true,
+		HideAliases:  true,
+		HideFlags:    true,
+		HideCommands: true,
+		HidePlugins:  true,
+	})
+	r.NoError(err)
+
+	exp := `$ main
+------
+
+This is how to use main.
+`
+	r.Equal(exp, bb.String())
+
+	bb.Reset()
+	err = PrintWith(bb, c, PrintOptions{
+		HideUsage:    true,
+		HideCommands: true,
+	})
+	r.NoError(err)
+
+	act := bb.String()
+	r.Contains(act, "plugcmd.cmd")
+	r.Contains(act, "Aliases:")
+	r.Contains(act, "My Flags")
+	r.Contains(act, "Using Plugins:")
+	r.NotContains(act, "This is how to use main.")
+	r.NotContains(act, "Available Commands:")
+}
+
+func Test_PrintWith_Template(t *testing.T) {
+	t.Parallel()
+	r := require.New(t)
+
+	tmpl := template.Must(template.New("").Funcs(HelpFuncs()).Parse(
+		`Usage: {{.Name}} [flags] <command>{{range .Commands}}
+  {{.Name}}{{end}}`,
+	))
+
+	bb := &bytes.Buffer{}
+	r.NoError(PrintWith(bb, cmd{name: "main"}, PrintOptions{Template: tmpl}))
+
+	exp := `Usage: main [flags] <command>
+  main sub1
+  main sub2
+  main sub3`
+	r.Equal(exp, bb.String())
+}
+
+func Test_PrintWith_HelpTemplater(t *testing.T) {
+	t.Parallel()
+	r := require.New(t)
+
+	c := templateCmd{
+		cmd:  cmd{name: "main"},
+		tmpl: `{{header .Name}} ({{join .Aliases "|"}})`,
+	}
+
+	bb := &bytes.Buffer{}
+	r.NoError(Print(bb, c))
+	r.Equal("$ main\n------ (main1|main2)", bb.String())
+
+	// the options' template wins
+	tmpl := template.Must(template.New("").Parse(`{{.Name}}`))
+
+	bb.Reset()
+	r.NoError(PrintWith(bb, c, PrintOptions{Template: tmpl}))
+	r.Equal("main", bb.String())
+
+	c.tmpl = "{{.Name"
+	err := Print(bb, c)
+	r.Error(err)
+	r.Contains(err.Error(), "unclosed action")
+}
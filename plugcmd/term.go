@@ -0,0 +1,326 @@
+package plugcmd
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"text/template"
+	"unicode/utf8"
+
+	"github.com/markbates/plugins"
+)
+
+// TermOptions control how PrintTerm renders help.
+type TermOptions struct {
+	PrintOptions
+
+	// Width to wrap the help at. If zero, the width is
+	// read from $COLUMNS, or the terminal itself.
+	Width int
+
+	// Color the headers and command names, unless
+	// $NO_COLOR is set.
+	Color bool
+
+	// Page help that is longer than the terminal
+	// through $PAGER.
+	Pager bool
+}
+
+// PrintTerm renders the help for the plugin, like
+// PrintWith, for a terminal. Descriptions and usage text
+// are wrapped to the width of the terminal, in aligned
+// columns, and can optionally be colored, or sent through
+// $PAGER.
+//
+// If w is not a terminal, and no Width is set, the
+// output is the same as PrintWith. Colors and paging
+// are only used when w is a terminal.
+func PrintTerm(w io.Writer, main plugins.Plugin, opts TermOptions) error {
+	f, tty := terminal(w)
+
+	width := opts.Width
+	if width <= 0 {
+		if !tty {
+			return PrintWith(w, main, opts.PrintOptions)
+		}
+		width = termWidth(f)
+	}
+
+	ts := &termStyle{
+		width: width,
+		color: opts.Color && tty && len(os.Getenv("NO_COLOR")) == 0,
+	}
+
+	if !opts.Pager || !tty {
+		return printWith(w, main, opts.PrintOptions, ts)
+	}
+
+	bb := &bytes.Buffer{}
+	if err := printWith(bb, main, opts.PrintOptions, ts); err != nil {
+		return err
+	}
+
+	return page(f, bb)
+}
+
+// isTerminal reports if the file is a terminal.
+var isTerminal = func(f *os.File) bool {
+	fi, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return fi.Mode()&os.ModeCharDevice != 0
+}
+
+func terminal(w io.Writer) (*os.File, bool) {
+	f, ok := w.(*os.File)
+	if !ok || f == nil {
+		return nil, false
+	}
+	return f, isTerminal(f)
+}
+
+func termWidth(f *os.File) int {
+	if n, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && n > 0 {
+		return n
+	}
+	if cols, _ := termSize(f); cols > 0 {
+		return cols
+	}
+	return 80
+}
+
+func termHeight(f *os.File) int {
+	if n, err := strconv.Atoi(os.Getenv("LINES")); err == nil && n > 0 {
+		return n
+	}
+	if _, rows := termSize(f); rows > 0 {
+		return rows
+	}
+	return 24
+}
+
+// page writes bb to f through $PAGER, if it is set and
+// bb is taller than the terminal.
+func page(f *os.File, bb *bytes.Buffer) error {
+	args := strings.Fields(os.Getenv("PAGER"))
+	if len(args) == 0 || bytes.Count(bb.Bytes(), []byte("\n")) < termHeight(f) {
+		_, err := bb.WriteTo(f)
+		return err
+	}
+
+	if _, err := exec.LookPath(args[0]); err != nil {
+		_, err := bb.WriteTo(f)
+		return err
+	}
+
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Stdin = bb
+	cmd.Stdout = f
+	cmd.Stderr = os.Stderr
+
+	// like git, let less pass colors through
+	// and quit if the help fits on one screen
+	if _, ok := os.LookupEnv("LESS"); !ok {
+		cmd.Env = append(os.Environ(), "LESS=FRX")
+	}
+
+	return cmd.Run()
+}
+
+const (
+	ansiBold  = "\x1b[1m"
+	ansiCyan  = "\x1b[36m"
+	ansiReset = "\x1b[0m"
+)
+
+// termStyle renders the HelpFuncs for a terminal.
+type termStyle struct {
+	width int
+	color bool
+}
+
+func (ts *termStyle) funcs() template.FuncMap {
+	return template.FuncMap{
+		"header": func(name string) string {
+			header := "$ " + strings.TrimSpace(name)
+			return ts.paint(ansiBold, header) + "\n" + strings.Repeat("-", utf8.RuneCountInString(header))
+		},
+		"section": func(title string) string {
+			return ts.paint(ansiBold, title+":")
+		},
+		"wrap": ts.wrap,
+		"join": strings.Join,
+		"aliases": func(aliases []HelpAlias) string {
+			return ts.table(aliasRows(aliases), 1)
+		},
+		"commands": func(cmds []HelpCommand) string {
+			groups := groupCommands(cmds)
+			if len(groups) == 1 && len(groups[0].Title) == 0 {
+				rows := [][]string{
+					{"Command", "Description"},
+					{"-------", "-----------"},
+				}
+				return ts.table(append(rows, commandRows(cmds)...), 1)
+			}
+
+			bb := &strings.Builder{}
+			for _, g := range groups {
+				bb.WriteString("\n" + ts.paint(ansiBold, g.Title+":") + "\n")
+				bb.WriteString(ts.table(commandRows(g.items), 1))
+			}
+			return bb.String()
+		},
+		"plugins": func(plugs []HelpPlugin) string {
+			rows := [][]string{
+				{"Name", "Description", "Type"},
+				{"----", "-----------", "----"},
+			}
+			for _, p := range plugs {
+				rows = append(rows, []string{p.Name, p.Description, p.Type})
+			}
+			return ts.table(rows, 1)
+		},
+		"sources": func(flags []HelpFlag) string {
+			return ts.table(sourceRows(flags), 1)
+		},
+	}
+}
+
+func commandRows(cmds []HelpCommand) [][]string {
+	rows := make([][]string, 0, len(cmds))
+	for _, c := range cmds {
+		rows = append(rows, []string{c.Name, c.note()})
+	}
+	return rows
+}
+
+func (ts *termStyle) paint(code string, s string) string {
+	if !ts.color || len(s) == 0 {
+		return s
+	}
+	return code + s + ansiReset
+}
+
+// wrap wraps each line of s that is wider than the
+// terminal, keeping the line's indentation.
+func (ts *termStyle) wrap(s string) string {
+	lines := strings.Split(s, "\n")
+
+	res := make([]string, 0, len(lines))
+	for _, line := range lines {
+		if utf8.RuneCountInString(line) <= ts.width {
+			res = append(res, line)
+			continue
+		}
+
+		text := strings.TrimLeft(line, " \t")
+		indent := line[:len(line)-len(text)]
+		for _, l := range wrapText(text, ts.width-utf8.RuneCountInString(indent)) {
+			res = append(res, indent+l)
+		}
+	}
+
+	return strings.Join(res, "\n")
+}
+
+// table renders rows in aligned columns, indented by
+// two spaces. The first two rows are the headers. If the
+// table is wider than the terminal, the cells in the
+// wrap column are wrapped. The names in the first column
+// are colored.
+func (ts *termStyle) table(rows [][]string, wrapCol int) string {
+	const indent = "  "
+	const gap = 2
+
+	var cols int
+	for _, row := range rows {
+		cols = max(cols, len(row))
+	}
+
+	widths := make([]int, cols)
+	for _, row := range rows {
+		for i, cell := range row {
+			widths[i] = max(widths[i], utf8.RuneCountInString(cell))
+		}
+	}
+
+	total := len(indent)
+	for i, w := range widths {
+		total += w
+		if i < cols-1 {
+			total += gap
+		}
+	}
+
+	if total > ts.width {
+		widths[wrapCol] = max(ts.width-(total-widths[wrapCol]), 10)
+	}
+
+	bb := &strings.Builder{}
+	for r, row := range rows {
+		cells := make([][]string, len(row))
+		height := 1
+		for i, cell := range row {
+			cells[i] = []string{cell}
+			if i == wrapCol {
+				cells[i] = wrapText(cell, widths[i])
+			}
+			height = max(height, len(cells[i]))
+		}
+
+		for l := 0; l < height; l++ {
+			line := &strings.Builder{}
+			line.WriteString(indent)
+			for i := range cells {
+				var text string
+				if l < len(cells[i]) {
+					text = cells[i][l]
+				}
+
+				pad := 0
+				if i < len(cells)-1 {
+					pad = widths[i] - utf8.RuneCountInString(text) + gap
+				}
+
+				if i == 0 && r > 1 {
+					text = ts.paint(ansiCyan, text)
+				}
+
+				line.WriteString(text)
+				line.WriteString(strings.Repeat(" ", max(pad, 0)))
+			}
+			bb.WriteString(strings.TrimRight(line.String(), " "))
+			bb.WriteString("\n")
+		}
+	}
+
+	return bb.String()
+}
+
+// wrapText splits s into lines of at most width runes,
+// breaking on spaces. Words wider than width are kept
+// on a line of their own.
+func wrapText(s string, width int) []string {
+	words := strings.Fields(s)
+	if len(words) == 0 {
+		return []string{""}
+	}
+
+	var lines []string
+	line := words[0]
+	for _, w := range words[1:] {
+		if utf8.RuneCountInString(line)+1+utf8.RuneCountInString(w) > width {
+			lines = append(lines, line)
+			line = w
+			continue
+		}
+		line += " " + w
+	}
+
+	return append(lines, line)
+}
@@ -0,0 +1,42 @@
+package plugcmd
+
+import (
+	"fmt"
+
+	"github.com/markbates/plugins"
+)
+
+// TermOptioner sets the TermOptions that Run prints help
+// with, see PrintTerm. Only its Width, Color, and Pager
+// are used, the PrintOptions are set by Run. Without one,
+// help is wrapped to the terminal, but not colored, or
+// paged.
+type TermOptioner interface {
+	plugins.Plugin
+	TermOptions() TermOptions
+}
+
+var _ TermOptioner = TermOptionerFn(nil)
+
+// TermOptionerFn is a function that can be used to implement the TermOptioner interface
+type TermOptionerFn func() TermOptions
+
+func (fn TermOptionerFn) TermOptions() TermOptions {
+	return fn()
+}
+
+func (fn TermOptionerFn) PluginName() string {
+	return fmt.Sprintf("%T", fn)
+}
+
+// runTermOptions returns the TermOptions of the first
+// TermOptioner in the collection, with the given opts.
+func runTermOptions(plugs plugins.Plugins, opts PrintOptions) TermOptions {
+	to := TermOptions{}
+	if tos := plugins.ByType[TermOptioner](plugs); len(tos) > 0 {
+		to = tos[0].TermOptions()
+	}
+
+	to.PrintOptions = opts
+	return to
+}
@@ -0,0 +1,56 @@
+package plugcmd
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/markbates/iox"
+	"github.com/markbates/plugins"
+	"github.com/markbates/plugins/plugtest"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_TermOptionerFn(t *testing.T) {
+	t.Parallel()
+	r := require.New(t)
+
+	exp := TermOptions{Color: true, Pager: true}
+	fn := TermOptionerFn(func() TermOptions {
+		return exp
+	})
+
+	act := fn.TermOptions()
+
+	r.Equal(exp, act)
+
+	r.Equal(fmt.Sprintf("%T", fn), fn.PluginName())
+}
+
+func Test_Run_TermOptioner(t *testing.T) {
+	t.Setenv("COLUMNS", "200")
+	t.Setenv("NO_COLOR", "")
+	r := require.New(t)
+
+	ctx := context.Background()
+
+	// no color, unless asked for
+	f := fakeTerminal(t)
+	plugs := plugins.Plugins{
+		termTree(),
+		&plugtest.IO{IO: iox.IO{Out: f}},
+	}
+	r.NoError(Run(ctx, "", []string{"app", "--help"}, plugs))
+	r.NotContains(readTerminal(t, f), "\x1b[")
+
+	f = fakeTerminal(t)
+	plugs = plugins.Plugins{
+		termTree(),
+		&plugtest.IO{IO: iox.IO{Out: f}},
+		TermOptionerFn(func() TermOptions {
+			return TermOptions{Color: true}
+		}),
+	}
+	r.NoError(Run(ctx, "", []string{"app", "--help"}, plugs))
+	r.Contains(readTerminal(t, f), ansiBold+"Available Commands:"+ansiReset)
+}
@@ -0,0 +1,20 @@
+//go:build !(darwin || dragonfly || freebsd || linux || netbsd || openbsd)
+
+package plugcmd
+
+import (
+	"errors"
+	"os"
+)
+
+// termSize returns zeros, the size of the terminal
+// is read from $COLUMNS and $LINES instead.
+func termSize(f *os.File) (int, int) {
+	return 0, 0
+}
+
+// makeRaw is not supported, the shell reads
+// whole lines instead.
+func makeRaw(f *os.File) (func() error, error) {
+	return nil, errors.ErrUnsupported
+}
@@ -0,0 +1,163 @@
+package plugcmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func termTree() Commander {
+	return docCmd{
+		name:  "app",
+		usage: "Run app with a long line of usage text that needs to be wrapped.",
+		subs: []Commander{
+			docCmd{
+				name: "build",
+				desc: "Build the application, and everything it needs, for production.",
+			},
+			docCmd{
+				name: "test",
+				desc: "Run tests",
+			},
+		},
+	}
+}
+
+// fakeTerminal returns a file that is treated as a terminal.
+func fakeTerminal(t *testing.T) *os.File {
+	t.Helper()
+
+	f, err := os.Create(filepath.Join(t.TempDir(), "tty"))
+	require.NoError(t, err)
+	t.Cleanup(func() { f.Close() })
+
+	orig := isTerminal
+	isTerminal = func(tf *os.File) bool {
+		return tf == f
+	}
+	t.Cleanup(func() { isTerminal = orig })
+
+	return f
+}
+
+func readTerminal(t *testing.T, f *os.File) string {
+	t.Helper()
+
+	b, err := os.ReadFile(f.Name())
+	require.NoError(t, err)
+	return string(b)
+}
+
+func Test_PrintTerm_Plain(t *testing.T) {
+	r := require.New(t)
+
+	exp := &bytes.Buffer{}
+	r.NoError(Print(exp, termTree()))
+
+	act := &bytes.Buffer{}
+	r.NoError(PrintTerm(act, termTree(), TermOptions{Color: true, Pager: true}))
+	r.Equal(exp.String(), act.String())
+}
+
+func Test_PrintTerm_Width(t *testing.T) {
+	r := require.New(t)
+
+	bb := &bytes.Buffer{}
+	r.NoError(PrintTerm(bb, termTree(), TermOptions{
+		PrintOptions: PrintOptions{HideType: true, HideFlags: true},
+		Width:        40,
+		Color:        true,
+	}))
+
+	exp := `$ app
+-----
+
+Run app with a long line of usage text
+that needs to be wrapped.
+
+Available Commands:
+  Command  Description
+  -------  -----------
+  build    Build the application, and
+           everything it needs, for
+           production.
+  test     Run tests
+`
+	r.Equal(exp, bb.String())
+}
+
+func Test_PrintTerm_Color(t *testing.T) {
+	t.Setenv("COLUMNS", "200")
+	t.Setenv("NO_COLOR", "")
+	r := require.New(t)
+
+	f := fakeTerminal(t)
+	r.NoError(PrintTerm(f, termTree(), TermOptions{Color: true}))
+
+	act := readTerminal(t, f)
+	r.Contains(act, ansiBold+"$ app"+ansiReset+"\n-----\n")
+	r.Contains(act, ansiBold+"Available Commands:"+ansiReset)
+	r.Contains(act, "  "+ansiCyan+"build"+ansiReset+"    Build the application")
+	r.Contains(act, "  -------  -----------\n")
+}
+
+func Test_PrintTerm_NoColor(t *testing.T) {
+	t.Setenv("COLUMNS", "200")
+	t.Setenv("NO_COLOR", "1")
+	r := require.New(t)
+
+	f := fakeTerminal(t)
+	r.NoError(PrintTerm(f, termTree(), TermOptions{Color: true}))
+
+	act := readTerminal(t, f)
+	r.NotContains(act, "\x1b[")
+	r.Contains(act, "  build    Build the application, and everything it needs, for production.\n")
+}
+
+func Test_PrintTerm_Pager(t *testing.T) {
+	t.Setenv("COLUMNS", "200")
+	t.Setenv("PAGER", "sed s/^/paged:/")
+	r := require.New(t)
+
+	t.Setenv("LINES", "5")
+
+	f := fakeTerminal(t)
+	r.NoError(PrintTerm(f, termTree(), TermOptions{Pager: true}))
+
+	act := readTerminal(t, f)
+	r.True(strings.HasPrefix(act, "paged:$ app\n"))
+	r.Contains(act, "paged:  test     Run tests\n")
+
+	// short help is not paged
+	t.Setenv("LINES", "100")
+
+	f = fakeTerminal(t)
+	r.NoError(PrintTerm(f, termTree(), TermOptions{Pager: true}))
+	r.True(strings.HasPrefix(readTerminal(t, f), "$ app\n"))
+}
+
+func Test_wrapText(t *testing.T) {
+	t.Parallel()
+
+	table := []struct {
+		in    string
+		width int
+		exp   []string
+	}{
+		{"", 10, []string{""}},
+		{"short", 10, []string{"short"}},
+		{"one two three", 7, []string{"one two", "three"}},
+		{"a supercalifragilistic word", 10, []string{"a", "supercalifragilistic", "word"}},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.in, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, tt.exp, wrapText(tt.in, tt.width))
+		})
+	}
+}
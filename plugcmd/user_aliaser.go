@@ -0,0 +1,28 @@
+package plugcmd
+
+import (
+	"fmt"
+
+	"github.com/markbates/plugins"
+)
+
+// UserAliaser provides aliases, and macros, defined by
+// the user, rather than by the commands themselves. See
+// LoadUserAliases.
+type UserAliaser interface {
+	plugins.Plugin
+	UserAliases() []UserAlias
+}
+
+var _ UserAliaser = UserAliaserFn(nil)
+
+// UserAliaserFn is a function that can be used to implement the UserAliaser interface
+type UserAliaserFn func() []UserAlias
+
+func (fn UserAliaserFn) UserAliases() []UserAlias {
+	return fn()
+}
+
+func (fn UserAliaserFn) PluginName() string {
+	return fmt.Sprintf("%T", fn)
+}
@@ -0,0 +1,26 @@
+package plugcmd
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_UserAliaserFn(t *testing.T) {
+	t.Parallel()
+	r := require.New(t)
+
+	exp := []UserAlias{
+		{Name: "ci", Steps: [][]string{{"build", "--race"}}},
+	}
+	fn := UserAliaserFn(func() []UserAlias {
+		return exp
+	})
+
+	act := fn.UserAliases()
+
+	r.Equal(exp, act)
+
+	r.Equal(fmt.Sprintf("%T", fn), fn.PluginName())
+}
@@ -0,0 +1,307 @@
+package plugcmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/markbates/plugins"
+)
+
+// UserAlias is an alias, or macro, defined by the user.
+// An alias has a single step, a macro runs each of its
+// steps, in order, stopping at the first error.
+type UserAlias struct {
+	Name  string
+	Steps [][]string
+	From  string // the file it was read from, if any
+}
+
+// String returns the steps of the alias, as they
+// would be written in a config file.
+func (a UserAlias) String() string {
+	steps := make([]string, 0, len(a.Steps))
+	for _, s := range a.Steps {
+		args := make([]string, 0, len(s))
+		for _, arg := range s {
+			args = append(args, quoteArg(arg))
+		}
+		steps = append(steps, strings.Join(args, " "))
+	}
+	return strings.Join(steps, " ; ")
+}
+
+// quoteArg quotes the arg, if needed, so
+// SplitArgs reads it back as it was.
+func quoteArg(s string) string {
+	if len(s) > 0 && !strings.ContainsAny(s, " \t\n\"'\\;$`") {
+		return s
+	}
+
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, `$`, `\$`, "`", "\\`")
+	return `"` + r.Replace(s) + `"`
+}
+
+// LoadUserAliases reads aliases, and macros, from the
+// config file name, see LoadConfig, in the app directory
+// of the user config directory, see os.UserConfigDir, and
+// then in root. Aliases in root replace those of the same
+// name in the user config directory.
+//
+//	LoadUserAliases(root, "app", "aliases.toml")
+//	// ~/.config/app/aliases.toml, then <root>/aliases.toml
+//
+//	[alias]
+//	ci = build --race ./...
+//
+//	[macro]
+//	release = test ./... ; build -tags prod ./...
+//
+// Aliases are split into args with SplitArgs. The steps
+// of a macro are separated by semicolons.
+func LoadUserAliases(root string, app string, name string) (UserAliaserFn, error) {
+	var dirs []string
+	if dir, err := os.UserConfigDir(); err == nil && len(app) > 0 {
+		dirs = append(dirs, filepath.Join(dir, app))
+	}
+	dirs = append(dirs, root)
+
+	mm := map[string]UserAlias{}
+	for _, dir := range dirs {
+		cfg, err := LoadConfig(dir, name)
+		if err != nil {
+			return nil, err
+		}
+
+		aliases, err := configAliases(cfg, dir, name)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, a := range aliases {
+			mm[a.Name] = a
+		}
+	}
+
+	res := make([]UserAlias, 0, len(mm))
+	for _, a := range mm {
+		res = append(res, a)
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Name < res[j].Name
+	})
+
+	return func() []UserAlias {
+		return res
+	}, nil
+}
+
+func configAliases(cfg Config, dir string, name string) ([]UserAlias, error) {
+	var res []UserAlias
+
+	for key, value := range cfg {
+		kind, alias, ok := strings.Cut(key, ".")
+		if !ok || (kind != "alias" && kind != "macro") {
+			continue
+		}
+
+		a := UserAlias{
+			Name: alias,
+			From: dirFile(dir, name),
+		}
+
+		var err error
+		if kind == "macro" {
+			a.Steps, err = splitSteps(value)
+		} else {
+			var args []string
+			args, err = SplitArgs(value)
+			a.Steps = [][]string{args}
+		}
+
+		if err == nil && len(a.Steps[0]) == 0 {
+			err = fmt.Errorf("nothing to run")
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s %s: %w", a.From, kind, alias, err)
+		}
+
+		res = append(res, a)
+	}
+
+	return res, nil
+}
+
+func dirFile(dir string, name string) string {
+	if filepath.IsAbs(name) {
+		return name
+	}
+	return filepath.Join(dir, name)
+}
+
+// splitSteps splits the line, at semicolons that are not
+// quoted, or escaped, and each step into args.
+func splitSteps(line string) ([][]string, error) {
+	var parts []string
+	var quote rune
+	var escape bool
+	start := 0
+
+	for i, r := range line {
+		switch {
+		case escape:
+			escape = false
+		case r == '\\' && quote != '\'':
+			escape = true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '\'' || r == '"':
+			quote = r
+		case r == ';':
+			parts = append(parts, line[start:i])
+			start = i + 1
+		}
+	}
+	parts = append(parts, line[start:])
+
+	steps := make([][]string, 0, len(parts))
+	for _, p := range parts {
+		args, err := SplitArgs(p)
+		if err != nil {
+			return nil, err
+		}
+		if len(args) == 0 {
+			return nil, fmt.Errorf("empty step: %s", line)
+		}
+		steps = append(steps, args)
+	}
+
+	return steps, nil
+}
+
+// AliasLoopError is returned when a user alias
+// expands into itself.
+type AliasLoopError struct {
+	Path []string // the aliases, in the order expanded
+}
+
+func (e *AliasLoopError) Error() string {
+	return fmt.Sprintf("alias loop: %s", strings.Join(e.Path, " -> "))
+}
+
+// ExpandAliases expands the first of the args, if it is a
+// user alias from the collection, and not the name of a
+// command. Aliases can expand into other aliases. The args
+// after the alias are added to its last step.
+//
+// Each step returned is the args to run, in order. If the
+// first arg is not an alias, args is the only step.
+func ExpandAliases(args []string, plugs plugins.Plugins) ([][]string, error) {
+	return expandAliases(args, userAliases(plugs), runRoot(plugs), nil)
+}
+
+func expandAliases(args []string, aliases map[string]UserAlias, top plugins.Plugin, seen []string) ([][]string, error) {
+	if len(args) == 0 {
+		return [][]string{args}, nil
+	}
+
+	name := args[0]
+	a, ok := aliases[name]
+	if !ok || isCommand(top, name) {
+		return [][]string{args}, nil
+	}
+
+	seen = append(slices.Clone(seen), name)
+	if slices.Contains(seen[:len(seen)-1], name) {
+		return nil, &AliasLoopError{Path: seen}
+	}
+
+	var res [][]string
+	for i, s := range a.Steps {
+		step := slices.Clone(s)
+		if i == len(a.Steps)-1 {
+			step = append(step, args[1:]...)
+		}
+
+		steps, err := expandAliases(step, aliases, top, seen)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, steps...)
+	}
+
+	return res, nil
+}
+
+// isCommand reports if the name matches a
+// sub-command of top.
+func isCommand(top plugins.Plugin, name string) bool {
+	sc, ok := top.(SubCommander)
+	if !ok {
+		return false
+	}
+
+	for _, c := range sc.SubCommands() {
+		if matches(name, c) {
+			return true
+		}
+	}
+	return false
+}
+
+// userAliases returns the user aliases in the collection,
+// by name. The first alias with a name wins.
+func userAliases(plugs plugins.Plugins) map[string]UserAlias {
+	mm := map[string]UserAlias{}
+	for _, ua := range plugins.ByType[UserAliaser](plugs) {
+		for _, a := range ua.UserAliases() {
+			if _, ok := mm[a.Name]; !ok {
+				mm[a.Name] = a
+			}
+		}
+	}
+	return mm
+}
+
+// userAliasList returns the user aliases in the
+// collection, sorted by name.
+func userAliasList(plugs plugins.Plugins) []UserAlias {
+	mm := userAliases(plugs)
+
+	res := make([]UserAlias, 0, len(mm))
+	for _, a := range mm {
+		res = append(res, a)
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Name < res[j].Name
+	})
+	return res
+}
+
+// aliasesFor returns the aliases with a step that
+// runs the command at path, or all of them if path
+// is empty.
+func aliasesFor(aliases []UserAlias, path []string) []UserAlias {
+	if len(path) == 0 {
+		return aliases
+	}
+
+	var res []UserAlias
+	for _, a := range aliases {
+		for _, s := range a.Steps {
+			if len(s) >= len(path) && slices.Equal(s[:len(path)], path) {
+				res = append(res, a)
+				break
+			}
+		}
+	}
+	return res
+}
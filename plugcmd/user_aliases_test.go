@@ -0,0 +1,243 @@
+package plugcmd
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/markbates/plugins"
+	"github.com/stretchr/testify/require"
+)
+
+func aliasPlugs(t *testing.T, aliases ...UserAlias) (plugins.Plugins, *[]string) {
+	t.Helper()
+
+	plugs, _, called, _ := runTree(t)
+	plugs = append(plugs, UserAliaserFn(func() []UserAlias {
+		return aliases
+	}))
+	return plugs, called
+}
+
+func Test_LoadUserAliases(t *testing.T) {
+	r := require.New(t)
+
+	home := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", home)
+	t.Setenv("HOME", home)
+
+	dir, err := os.UserConfigDir()
+	r.NoError(err)
+	r.NoError(os.MkdirAll(filepath.Join(dir, "app"), 0755))
+
+	user := "[alias]\nci = build --race ./...\nst = status\n"
+	r.NoError(os.WriteFile(filepath.Join(dir, "app", "config.toml"), []byte(user), 0644))
+
+	root := t.TempDir()
+
+	local := `[alias]
+ci = build -tags "dev prod"
+
+[macro]
+release = test ./... ; build "a;b" ./...
+
+[build]
+tags = dev
+`
+	r.NoError(os.WriteFile(filepath.Join(root, "config.toml"), []byte(local), 0644))
+
+	fn, err := LoadUserAliases(root, "app", "config.toml")
+	r.NoError(err)
+
+	r.Equal([]UserAlias{
+		{
+			Name:  "ci",
+			Steps: [][]string{{"build", "-tags", "dev prod"}},
+			From:  filepath.Join(root, "config.toml"),
+		},
+		{
+			Name:  "release",
+			Steps: [][]string{{"test", "./..."}, {"build", "a;b", "./..."}},
+			From:  filepath.Join(root, "config.toml"),
+		},
+		{
+			Name:  "st",
+			Steps: [][]string{{"status"}},
+			From:  filepath.Join(dir, "app", "config.toml"),
+		},
+	}, fn.UserAliases())
+
+	r.NoError(os.WriteFile(filepath.Join(root, "bad.toml"), []byte("[macro]\nx = a ; ; b\n"), 0644))
+	_, err = LoadUserAliases(root, "app", "bad.toml")
+	r.Error(err)
+	r.Contains(err.Error(), "macro x: empty step")
+
+	r.NoError(os.WriteFile(filepath.Join(root, "empty.toml"), []byte("[alias]\nx = \"\"\n"), 0644))
+	_, err = LoadUserAliases(root, "app", "empty.toml")
+	r.Error(err)
+	r.Contains(err.Error(), "alias x: nothing to run")
+}
+
+func Test_UserAlias_String(t *testing.T) {
+	t.Parallel()
+	r := require.New(t)
+
+	a := UserAlias{
+		Name:  "x",
+		Steps: [][]string{{"build", "-tags", "dev prod", ""}, {"say", `a"b`, "$HOME", "a;b"}},
+	}
+
+	s := a.String()
+	r.Equal(`build -tags "dev prod" "" ; say "a\"b" "\$HOME" "a;b"`, s)
+
+	steps, err := splitSteps(s)
+	r.NoError(err)
+	r.Equal(a.Steps, steps)
+}
+
+func Test_ExpandAliases(t *testing.T) {
+	t.Parallel()
+
+	plugs, _ := aliasPlugs(t,
+		UserAlias{Name: "ci", Steps: [][]string{{"a", "b", "-v"}}},
+		UserAlias{Name: "cc", Steps: [][]string{{"ci", "c"}}},
+		UserAlias{Name: "both", Steps: [][]string{{"cc", "one"}, {"ci", "c", "two"}}},
+		UserAlias{Name: "a", Steps: [][]string{{"never"}}},
+		UserAlias{Name: "loop", Steps: [][]string{{"pool"}}},
+		UserAlias{Name: "pool", Steps: [][]string{{"a"}, {"loop"}}},
+	)
+
+	table := []struct {
+		name string
+		args []string
+		exp  [][]string
+		err  string
+	}{
+		{name: "none", args: nil, exp: [][]string{nil}},
+		{name: "command", args: []string{"a", "b"}, exp: [][]string{{"a", "b"}}},
+		{name: "alias", args: []string{"ci", "c"}, exp: [][]string{{"a", "b", "-v", "c"}}},
+		{name: "nested", args: []string{"cc", "x"}, exp: [][]string{{"a", "b", "-v", "c", "x"}}},
+		{name: "macro", args: []string{"both", "x"}, exp: [][]string{
+			{"a", "b", "-v", "c", "one"},
+			{"a", "b", "-v", "c", "two", "x"},
+		}},
+		{name: "loop", args: []string{"loop"}, err: "alias loop: loop -> pool -> loop"},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			r := require.New(t)
+
+			steps, err := ExpandAliases(tt.args, plugs)
+			if len(tt.err) > 0 {
+				r.Error(err)
+				r.Equal(tt.err, err.Error())
+
+				var le *AliasLoopError
+				r.ErrorAs(err, &le)
+				return
+			}
+			r.NoError(err)
+			r.Equal(tt.exp, steps)
+		})
+	}
+}
+
+func Test_Find_UserAlias(t *testing.T) {
+	t.Parallel()
+	r := require.New(t)
+
+	plugs, _ := aliasPlugs(t,
+		UserAlias{Name: "ci", Steps: [][]string{{"a", "b"}}},
+		UserAlias{Name: "loop", Steps: [][]string{{"loop"}}},
+	)
+
+	c := Find("ci", plugs)
+	r.NotNil(c)
+	r.Equal("a", cmdName(c))
+
+	c = FindFromArgs([]string{"-v", "ci", "x"}, plugs)
+	r.NotNil(c)
+	r.Equal("a", cmdName(c))
+
+	r.Nil(Find("loop", plugs))
+	r.Nil(Find("nope", plugs))
+	r.NotNil(Find("a", plugs))
+
+	c, args, err := FindArgs([]string{"-v", "ci", "x"}, plugs)
+	r.NoError(err)
+	r.Equal("a", cmdName(c))
+	r.Equal([]string{"b", "x"}, args)
+
+	c, args, err = FindArgs([]string{"a", "c"}, plugs)
+	r.NoError(err)
+	r.Equal("a", cmdName(c))
+	r.Equal([]string{"c"}, args)
+
+	_, _, err = FindArgs([]string{"loop"}, plugs)
+	var le *AliasLoopError
+	r.ErrorAs(err, &le)
+
+	c, _, err = FindArgs([]string{"nope"}, plugs)
+	r.NoError(err)
+	r.Nil(c)
+}
+
+func Test_Run_UserAlias(t *testing.T) {
+	t.Parallel()
+	r := require.New(t)
+
+	plugs, called := aliasPlugs(t,
+		UserAlias{Name: "ci", Steps: [][]string{{"a", "b", "c"}}},
+		UserAlias{Name: "both", Steps: [][]string{{"ci", "one"}, {"ci", "fail"}, {"ci", "never"}}},
+		UserAlias{Name: "loop", Steps: [][]string{{"loop"}}},
+	)
+
+	ctx := context.Background()
+
+	r.NoError(Run(ctx, "", []string{"ci", "x"}, plugs))
+	r.Equal([]string{"c x"}, *called)
+
+	*called = nil
+	err := Run(ctx, "", []string{"both"}, plugs)
+	r.ErrorIs(err, ErrFailed)
+	r.Equal([]string{"c one", "c fail"}, *called)
+
+	err = Run(ctx, "", []string{"loop"}, plugs)
+	r.ErrorIs(err, ErrUsage)
+	r.Contains(err.Error(), "alias loop: loop -> loop")
+}
+
+func Test_Print_UserAliases(t *testing.T) {
+	t.Parallel()
+	r := require.New(t)
+
+	plugs, _ := aliasPlugs(t,
+		UserAlias{Name: "ci", Steps: [][]string{{"a", "b", "-v"}}},
+		UserAlias{Name: "other", Steps: [][]string{{"x"}}},
+	)
+
+	bb := &bytes.Buffer{}
+	r.NoError(Print(bb, runRoot(plugs)))
+	r.Contains(bb.String(), "\nUser Aliases:\n  Alias  Command\n  -----  -------\n  ci     a b -v\n  other  x\n")
+
+	bb.Reset()
+	r.NoError(PrintWith(bb, plugs[0], PrintOptions{
+		UserAliases: aliasesFor(userAliasList(plugs), []string{"a"}),
+	}))
+	r.Contains(bb.String(), "  ci     a b -v\n")
+	r.NotContains(bb.String(), "other")
+
+	bb.Reset()
+	r.NoError(PrintWith(bb, runRoot(plugs), PrintOptions{HideUserAliases: true}))
+	r.NotContains(bb.String(), "User Aliases")
+
+	m, err := Describe(runRoot(plugs))
+	r.NoError(err)
+	r.Equal([]HelpAlias{
+		{Name: "ci", Command: "a b -v"},
+		{Name: "other", Command: "x"},
+	}, m.UserAliases)
+}
@@ -0,0 +1,119 @@
+package plugcmd
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"text/tabwriter"
+
+	"github.com/markbates/plugins"
+)
+
+var _ Commander = &Version{}
+var _ Describer = &Version{}
+var _ Namer = &Version{}
+var _ plugins.IOSetable = &Version{}
+var _ plugins.Needer = &Version{}
+
+// Version is the built-in `version` command. It prints
+// the BuildVersion of the running binary.
+//
+//	Path:      github.com/foo/bar/cmd/bar
+//	Module:    github.com/foo/bar
+//	Version:   v1.2.3
+//	Revision:  1a2b3c4d (modified)
+//	Time:      2024-01-02T03:04:05Z
+//	Go:        go1.24.0
+type Version struct {
+	builtin
+}
+
+func (v *Version) PluginName() string {
+	return "plugcmd/version"
+}
+
+func (v *Version) CmdName() string {
+	return "version"
+}
+
+func (v *Version) Description() string {
+	return "Print the version information"
+}
+
+func (v *Version) Main(ctx context.Context, root string, args []string) error {
+	var plugs plugins.Plugins
+	if v.feeder != nil {
+		plugs = v.feeder()
+	}
+
+	bv, ok := ReadBuildVersion()
+	if !ok {
+		return fmt.Errorf("no build information available")
+	}
+
+	rev := bv.Revision
+	if bv.Modified {
+		rev += " (modified)"
+	}
+
+	tw := tabwriter.NewWriter(v.stdout(plugs), 0, 0, 2, ' ', 0)
+	for _, row := range [][2]string{
+		{"Path", bv.Path},
+		{"Module", bv.Module},
+		{"Version", bv.Version},
+		{"Revision", rev},
+		{"Time", bv.Time},
+		{"Go", bv.GoVersion},
+	} {
+		if len(row[1]) == 0 {
+			continue
+		}
+		fmt.Fprintf(tw, "%s:\t%s\n", row[0], row[1])
+	}
+	return tw.Flush()
+}
+
+// BuildVersion is the version of the running binary,
+// from its build information.
+type BuildVersion struct {
+	Path      string `json:"path"`               // the main package
+	Module    string `json:"module"`             // the main module
+	Version   string `json:"version"`            // the main module version
+	Revision  string `json:"revision,omitempty"` // the VCS revision
+	Time      string `json:"time,omitempty"`     // the time of the revision
+	Modified  bool   `json:"modified,omitempty"` // uncommitted changes
+	GoVersion string `json:"go_version"`
+}
+
+// readBuildInfo is debug.ReadBuildInfo,
+// replaced in tests.
+var readBuildInfo = debug.ReadBuildInfo
+
+// ReadBuildVersion returns the BuildVersion of the
+// running binary, if it was built with module support.
+func ReadBuildVersion() (BuildVersion, bool) {
+	bi, ok := readBuildInfo()
+	if !ok || bi == nil {
+		return BuildVersion{}, false
+	}
+
+	bv := BuildVersion{
+		Path:      bi.Path,
+		Module:    bi.Main.Path,
+		Version:   bi.Main.Version,
+		GoVersion: bi.GoVersion,
+	}
+
+	for _, s := range bi.Settings {
+		switch s.Key {
+		case "vcs.revision":
+			bv.Revision = s.Value
+		case "vcs.time":
+			bv.Time = s.Value
+		case "vcs.modified":
+			bv.Modified = s.Value == "true"
+		}
+	}
+
+	return bv, true
+}
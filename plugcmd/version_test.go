@@ -0,0 +1,86 @@
+package plugcmd
+
+import (
+	"bytes"
+	"context"
+	"runtime/debug"
+	"testing"
+
+	"github.com/markbates/iox"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Version(t *testing.T) {
+	r := require.New(t)
+
+	rbi := readBuildInfo
+	t.Cleanup(func() {
+		readBuildInfo = rbi
+	})
+
+	readBuildInfo = func() (*debug.BuildInfo, bool) {
+		return &debug.BuildInfo{
+			GoVersion: "go1.24.0",
+			Path:      "github.com/foo/bar/cmd/bar",
+			Main: debug.Module{
+				Path:    "github.com/foo/bar",
+				Version: "v1.2.3",
+			},
+			Settings: []debug.BuildSetting{
+				{Key: "vcs.revision", Value: "1a2b3c4d"},
+				{Key: "vcs.time", Value: "2024-01-02T03:04:05Z"},
+				{Key: "vcs.modified", Value: "true"},
+			},
+		}, true
+	}
+
+	bv, ok := ReadBuildVersion()
+	r.True(ok)
+	r.Equal(BuildVersion{
+		Path:      "github.com/foo/bar/cmd/bar",
+		Module:    "github.com/foo/bar",
+		Version:   "v1.2.3",
+		Revision:  "1a2b3c4d",
+		Time:      "2024-01-02T03:04:05Z",
+		Modified:  true,
+		GoVersion: "go1.24.0",
+	}, bv)
+
+	bb := &iox.Buffer{}
+	v := &Version{}
+	r.NoError(v.SetStdio(bb.IO()))
+	r.NoError(v.Main(context.Background(), "", nil))
+
+	exp := `Path:      github.com/foo/bar/cmd/bar
+Module:    github.com/foo/bar
+Version:   v1.2.3
+Revision:  1a2b3c4d (modified)
+Time:      2024-01-02T03:04:05Z
+Go:        go1.24.0
+`
+	r.Equal(exp, bb.Out.String())
+
+	readBuildInfo = func() (*debug.BuildInfo, bool) {
+		return nil, false
+	}
+
+	_, ok = ReadBuildVersion()
+	r.False(ok)
+	r.Error(v.Main(context.Background(), "", nil))
+}
+
+func Test_Version_Test(t *testing.T) {
+	t.Parallel()
+	r := require.New(t)
+
+	// test binaries have build info too
+	bv, ok := ReadBuildVersion()
+	r.True(ok)
+	r.NotEmpty(bv.GoVersion)
+
+	bb := &bytes.Buffer{}
+	v := &Version{}
+	r.NoError(v.SetStdio(iox.IO{Out: bb}))
+	r.NoError(v.Main(context.Background(), "", nil))
+	r.Contains(bb.String(), "Go:")
+}
@@ -0,0 +1,186 @@
+package plugcmd
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"runtime/debug"
+	"time"
+
+	"github.com/markbates/plugins"
+)
+
+//go:generate go run ./internal/wrapgen wrap_gen.go
+
+// Middleware decorates the Main of cmd. It is given the
+// original, unwrapped, command, and the next Main in the
+// chain, and returns the Main to call instead.
+type Middleware func(cmd Commander, next CommanderFn) CommanderFn
+
+// Wrap returns cmd with its Main decorated by the
+// middleware. The first middleware is the outermost.
+//
+// The returned Commander implements the same Namer,
+// Aliaser, Describer, Flagger, and SubCommander
+// interfaces as cmd, so Find works for it as it does for
+// cmd. It is a plugins.Unwrapper, so plugins.ByType,
+// plugins.As, and the methods of plugins.Plugins, such as
+// Available, Validate, SetStdio, Start, and Stop, as well
+// as Print, Run, and the other functions of this package,
+// look through it for the rest of the interfaces cmd
+// implements, and treat it just like cmd.
+//
+//	cmd = plugcmd.Wrap(cmd,
+//		plugcmd.Recover(),
+//		plugcmd.Timing(nil),
+//		plugcmd.Timeout(time.Minute),
+//	)
+func Wrap(cmd Commander, mw ...Middleware) Commander {
+	if cmd == nil {
+		return nil
+	}
+
+	main := CommanderFn(cmd.Main)
+	for i := len(mw) - 1; i >= 0; i-- {
+		if mw[i] == nil {
+			continue
+		}
+		main = mw[i](cmd, main)
+	}
+
+	return wrapFor(&wrapper{cmd: cmd, main: main})
+}
+
+// Unwrap returns the Commander that was given to Wrap,
+// or cmd itself if it was not wrapped.
+func Unwrap(cmd Commander) Commander {
+	for {
+		w, ok := cmd.(interface{ Unwrap() Commander })
+		if !ok {
+			return cmd
+		}
+		cmd = w.Unwrap()
+	}
+}
+
+// unwrapPlugin returns the Commander wrapped by the
+// plugin, if it is one returned by Wrap, otherwise
+// it returns the plugin.
+func unwrapPlugin(p plugins.Plugin) plugins.Plugin {
+	if c, ok := p.(Commander); ok {
+		return Unwrap(c)
+	}
+	return p
+}
+
+var _ plugins.Unwrapper = &wrapper{}
+
+// wrapper is the base of the types, in wrap_gen.go,
+// returned by Wrap.
+type wrapper struct {
+	cmd  Commander
+	main CommanderFn
+}
+
+func (w *wrapper) PluginName() string {
+	return w.cmd.PluginName()
+}
+
+func (w *wrapper) Main(ctx context.Context, root string, args []string) error {
+	return w.main(ctx, root, args)
+}
+
+func (w *wrapper) Unwrap() Commander {
+	return w.cmd
+}
+
+func (w *wrapper) UnwrapPlugin() plugins.Plugin {
+	return w.cmd
+}
+
+// PanicError is returned by the Recover middleware
+// when a command panics.
+type PanicError struct {
+	Name  string
+	Value any
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("%s: panic: %v", e.Name, e.Value)
+}
+
+// Unwrap returns the value of the panic, if it
+// is an error.
+func (e *PanicError) Unwrap() error {
+	err, _ := e.Value.(error)
+	return err
+}
+
+// Recover returns a Middleware that turns a panic in the
+// command into a *PanicError.
+func Recover() Middleware {
+	return func(cmd Commander, next CommanderFn) CommanderFn {
+		return func(ctx context.Context, root string, args []string) (err error) {
+			defer func() {
+				if r := recover(); r != nil {
+					err = &PanicError{
+						Name:  cmdName(cmd),
+						Value: r,
+						Stack: debug.Stack(),
+					}
+				}
+			}()
+			return next(ctx, root, args)
+		}
+	}
+}
+
+// Timing returns a Middleware that logs how long the
+// command took, and its error, if any. If logger is nil,
+// slog.Default is used.
+func Timing(logger *slog.Logger) Middleware {
+	return func(cmd Commander, next CommanderFn) CommanderFn {
+		return func(ctx context.Context, root string, args []string) error {
+			l := logger
+			if l == nil {
+				l = slog.Default()
+			}
+
+			start := time.Now()
+			err := next(ctx, root, args)
+
+			attrs := []slog.Attr{
+				slog.String("command", cmdName(cmd)),
+				slog.Duration("duration", time.Since(start)),
+			}
+
+			level := slog.LevelInfo
+			if err != nil {
+				level = slog.LevelError
+				attrs = append(attrs, slog.Any("error", err))
+			}
+
+			l.LogAttrs(ctx, level, "command finished", attrs...)
+			return err
+		}
+	}
+}
+
+// Timeout returns a Middleware that cancels the context
+// given to the command after d. Commands are expected to
+// honor the context, Timeout does not stop them.
+func Timeout(d time.Duration) Middleware {
+	return func(cmd Commander, next CommanderFn) CommanderFn {
+		return func(ctx context.Context, root string, args []string) error {
+			if d <= 0 {
+				return next(ctx, root, args)
+			}
+
+			ctx, cancel := context.WithTimeout(ctx, d)
+			defer cancel()
+
+			return next(ctx, root, args)
+		}
+	}
+}
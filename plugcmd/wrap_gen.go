@@ -0,0 +1,494 @@
+// Code generated by wrapgen. DO NOT EDIT.
+
+package plugcmd
+
+import "flag"
+
+// wrapFor returns w as a type that implements
+// the same optional interfaces as w.cmd.
+func wrapFor(w *wrapper) Commander {
+	var mask int
+	if _, ok := w.cmd.(Namer); ok {
+		mask |= 1
+	}
+	if _, ok := w.cmd.(Aliaser); ok {
+		mask |= 2
+	}
+	if _, ok := w.cmd.(Describer); ok {
+		mask |= 4
+	}
+	if _, ok := w.cmd.(Flagger); ok {
+		mask |= 8
+	}
+	if _, ok := w.cmd.(SubCommander); ok {
+		mask |= 16
+	}
+
+	switch mask {
+	case 1:
+		return wrapNamer{w}
+	case 2:
+		return wrapAliaser{w}
+	case 3:
+		return wrapNamerAliaser{w}
+	case 4:
+		return wrapDescriber{w}
+	case 5:
+		return wrapNamerDescriber{w}
+	case 6:
+		return wrapAliaserDescriber{w}
+	case 7:
+		return wrapNamerAliaserDescriber{w}
+	case 8:
+		return wrapFlagger{w}
+	case 9:
+		return wrapNamerFlagger{w}
+	case 10:
+		return wrapAliaserFlagger{w}
+	case 11:
+		return wrapNamerAliaserFlagger{w}
+	case 12:
+		return wrapDescriberFlagger{w}
+	case 13:
+		return wrapNamerDescriberFlagger{w}
+	case 14:
+		return wrapAliaserDescriberFlagger{w}
+	case 15:
+		return wrapNamerAliaserDescriberFlagger{w}
+	case 16:
+		return wrapSubCommander{w}
+	case 17:
+		return wrapNamerSubCommander{w}
+	case 18:
+		return wrapAliaserSubCommander{w}
+	case 19:
+		return wrapNamerAliaserSubCommander{w}
+	case 20:
+		return wrapDescriberSubCommander{w}
+	case 21:
+		return wrapNamerDescriberSubCommander{w}
+	case 22:
+		return wrapAliaserDescriberSubCommander{w}
+	case 23:
+		return wrapNamerAliaserDescriberSubCommander{w}
+	case 24:
+		return wrapFlaggerSubCommander{w}
+	case 25:
+		return wrapNamerFlaggerSubCommander{w}
+	case 26:
+		return wrapAliaserFlaggerSubCommander{w}
+	case 27:
+		return wrapNamerAliaserFlaggerSubCommander{w}
+	case 28:
+		return wrapDescriberFlaggerSubCommander{w}
+	case 29:
+		return wrapNamerDescriberFlaggerSubCommander{w}
+	case 30:
+		return wrapAliaserDescriberFlaggerSubCommander{w}
+	case 31:
+		return wrapNamerAliaserDescriberFlaggerSubCommander{w}
+	}
+	return w
+}
+
+func (w *wrapper) cmdName() string {
+	return w.cmd.(Namer).CmdName()
+}
+
+func (w *wrapper) cmdAliases() []string {
+	return w.cmd.(Aliaser).CmdAliases()
+}
+
+func (w *wrapper) description() string {
+	return w.cmd.(Describer).Description()
+}
+
+func (w *wrapper) flags() (*flag.FlagSet, error) {
+	return w.cmd.(Flagger).Flags()
+}
+
+func (w *wrapper) subCommands() []Commander {
+	return w.cmd.(SubCommander).SubCommands()
+}
+
+type wrapNamer struct{ *wrapper }
+
+func (w wrapNamer) CmdName() string {
+	return w.cmdName()
+}
+
+type wrapAliaser struct{ *wrapper }
+
+func (w wrapAliaser) CmdAliases() []string {
+	return w.cmdAliases()
+}
+
+type wrapNamerAliaser struct{ *wrapper }
+
+func (w wrapNamerAliaser) CmdName() string {
+	return w.cmdName()
+}
+
+func (w wrapNamerAliaser) CmdAliases() []string {
+	return w.cmdAliases()
+}
+
+type wrapDescriber struct{ *wrapper }
+
+func (w wrapDescriber) Description() string {
+	return w.description()
+}
+
+type wrapNamerDescriber struct{ *wrapper }
+
+func (w wrapNamerDescriber) CmdName() string {
+	return w.cmdName()
+}
+
+func (w wrapNamerDescriber) Description() string {
+	return w.description()
+}
+
+type wrapAliaserDescriber struct{ *wrapper }
+
+func (w wrapAliaserDescriber) CmdAliases() []string {
+	return w.cmdAliases()
+}
+
+func (w wrapAliaserDescriber) Description() string {
+	return w.description()
+}
+
+type wrapNamerAliaserDescriber struct{ *wrapper }
+
+func (w wrapNamerAliaserDescriber) CmdName() string {
+	return w.cmdName()
+}
+
+func (w wrapNamerAliaserDescriber) CmdAliases() []string {
+	return w.cmdAliases()
+}
+
+func (w wrapNamerAliaserDescriber) Description() string {
+	return w.description()
+}
+
+type wrapFlagger struct{ *wrapper }
+
+func (w wrapFlagger) Flags() (*flag.FlagSet, error) {
+	return w.flags()
+}
+
+type wrapNamerFlagger struct{ *wrapper }
+
+func (w wrapNamerFlagger) CmdName() string {
+	return w.cmdName()
+}
+
+func (w wrapNamerFlagger) Flags() (*flag.FlagSet, error) {
+	return w.flags()
+}
+
+type wrapAliaserFlagger struct{ *wrapper }
+
+func (w wrapAliaserFlagger) CmdAliases() []string {
+	return w.cmdAliases()
+}
+
+func (w wrapAliaserFlagger) Flags() (*flag.FlagSet, error) {
+	return w.flags()
+}
+
+type wrapNamerAliaserFlagger struct{ *wrapper }
+
+func (w wrapNamerAliaserFlagger) CmdName() string {
+	return w.cmdName()
+}
+
+func (w wrapNamerAliaserFlagger) CmdAliases() []string {
+	return w.cmdAliases()
+}
+
+func (w wrapNamerAliaserFlagger) Flags() (*flag.FlagSet, error) {
+	return w.flags()
+}
+
+type wrapDescriberFlagger struct{ *wrapper }
+
+func (w wrapDescriberFlagger) Description() string {
+	return w.description()
+}
+
+func (w wrapDescriberFlagger) Flags() (*flag.FlagSet, error) {
+	return w.flags()
+}
+
+type wrapNamerDescriberFlagger struct{ *wrapper }
+
+func (w wrapNamerDescriberFlagger) CmdName() string {
+	return w.cmdName()
+}
+
+func (w wrapNamerDescriberFlagger) Description() string {
+	return w.description()
+}
+
+func (w wrapNamerDescriberFlagger) Flags() (*flag.FlagSet, error) {
+	return w.flags()
+}
+
+type wrapAliaserDescriberFlagger struct{ *wrapper }
+
+func (w wrapAliaserDescriberFlagger) CmdAliases() []string {
+	return w.cmdAliases()
+}
+
+func (w wrapAliaserDescriberFlagger) Description() string {
+	return w.description()
+}
+
+func (w wrapAliaserDescriberFlagger) Flags() (*flag.FlagSet, error) {
+	return w.flags()
+}
+
+type wrapNamerAliaserDescriberFlagger struct{ *wrapper }
+
+func (w wrapNamerAliaserDescriberFlagger) CmdName() string {
+	return w.cmdName()
+}
+
+func (w wrapNamerAliaserDescriberFlagger) CmdAliases() []string {
+	return w.cmdAliases()
+}
+
+func (w wrapNamerAliaserDescriberFlagger) Description() string {
+	return w.description()
+}
+
+func (w wrapNamerAliaserDescriberFlagger) Flags() (*flag.FlagSet, error) {
+	return w.flags()
+}
+
+type wrapSubCommander struct{ *wrapper }
+
+func (w wrapSubCommander) SubCommands() []Commander {
+	return w.subCommands()
+}
+
+type wrapNamerSubCommander struct{ *wrapper }
+
+func (w wrapNamerSubCommander) CmdName() string {
+	return w.cmdName()
+}
+
+func (w wrapNamerSubCommander) SubCommands() []Commander {
+	return w.subCommands()
+}
+
+type wrapAliaserSubCommander struct{ *wrapper }
+
+func (w wrapAliaserSubCommander) CmdAliases() []string {
+	return w.cmdAliases()
+}
+
+func (w wrapAliaserSubCommander) SubCommands() []Commander {
+	return w.subCommands()
+}
+
+type wrapNamerAliaserSubCommander struct{ *wrapper }
+
+func (w wrapNamerAliaserSubCommander) CmdName() string {
+	return w.cmdName()
+}
+
+func (w wrapNamerAliaserSubCommander) CmdAliases() []string {
+	return w.cmdAliases()
+}
+
+func (w wrapNamerAliaserSubCommander) SubCommands() []Commander {
+	return w.subCommands()
+}
+
+type wrapDescriberSubCommander struct{ *wrapper }
+
+func (w wrapDescriberSubCommander) Description() string {
+	return w.description()
+}
+
+func (w wrapDescriberSubCommander) SubCommands() []Commander {
+	return w.subCommands()
+}
+
+type wrapNamerDescriberSubCommander struct{ *wrapper }
+
+func (w wrapNamerDescriberSubCommander) CmdName() string {
+	return w.cmdName()
+}
+
+func (w wrapNamerDescriberSubCommander) Description() string {
+	return w.description()
+}
+
+func (w wrapNamerDescriberSubCommander) SubCommands() []Commander {
+	return w.subCommands()
+}
+
+type wrapAliaserDescriberSubCommander struct{ *wrapper }
+
+func (w wrapAliaserDescriberSubCommander) CmdAliases() []string {
+	return w.cmdAliases()
+}
+
+func (w wrapAliaserDescriberSubCommander) Description() string {
+	return w.description()
+}
+
+func (w wrapAliaserDescriberSubCommander) SubCommands() []Commander {
+	return w.subCommands()
+}
+
+type wrapNamerAliaserDescriberSubCommander struct{ *wrapper }
+
+func (w wrapNamerAliaserDescriberSubCommander) CmdName() string {
+	return w.cmdName()
+}
+
+func (w wrapNamerAliaserDescriberSubCommander) CmdAliases() []string {
+	return w.cmdAliases()
+}
+
+func (w wrapNamerAliaserDescriberSubCommander) Description() string {
+	return w.description()
+}
+
+func (w wrapNamerAliaserDescriberSubCommander) SubCommands() []Commander {
+	return w.subCommands()
+}
+
+type wrapFlaggerSubCommander struct{ *wrapper }
+
+func (w wrapFlaggerSubCommander) Flags() (*flag.FlagSet, error) {
+	return w.flags()
+}
+
+func (w wrapFlaggerSubCommander) SubCommands() []Commander {
+	return w.subCommands()
+}
+
+type wrapNamerFlaggerSubCommander struct{ *wrapper }
+
+func (w wrapNamerFlaggerSubCommander) CmdName() string {
+	return w.cmdName()
+}
+
+func (w wrapNamerFlaggerSubCommander) Flags() (*flag.FlagSet, error) {
+	return w.flags()
+}
+
+func (w wrapNamerFlaggerSubCommander) SubCommands() []Commander {
+	return w.subCommands()
+}
+
+type wrapAliaserFlaggerSubCommander struct{ *wrapper }
+
+func (w wrapAliaserFlaggerSubCommander) CmdAliases() []string {
+	return w.cmdAliases()
+}
+
+func (w wrapAliaserFlaggerSubCommander) Flags() (*flag.FlagSet, error) {
+	return w.flags()
+}
+
+func (w wrapAliaserFlaggerSubCommander) SubCommands() []Commander {
+	return w.subCommands()
+}
+
+type wrapNamerAliaserFlaggerSubCommander struct{ *wrapper }
+
+func (w wrapNamerAliaserFlaggerSubCommander) CmdName() string {
+	return w.cmdName()
+}
+
+func (w wrapNamerAliaserFlaggerSubCommander) CmdAliases() []string {
+	return w.cmdAliases()
+}
+
+func (w wrapNamerAliaserFlaggerSubCommander) Flags() (*flag.FlagSet, error) {
+	return w.flags()
+}
+
+func (w wrapNamerAliaserFlaggerSubCommander) SubCommands() []Commander {
+	return w.subCommands()
+}
+
+type wrapDescriberFlaggerSubCommander struct{ *wrapper }
+
+func (w wrapDescriberFlaggerSubCommander) Description() string {
+	return w.description()
+}
+
+func (w wrapDescriberFlaggerSubCommander) Flags() (*flag.FlagSet, error) {
+	return w.flags()
+}
+
+func (w wrapDescriberFlaggerSubCommander) SubCommands() []Commander {
+	return w.subCommands()
+}
+
+type wrapNamerDescriberFlaggerSubCommander struct{ *wrapper }
+
+func (w wrapNamerDescriberFlaggerSubCommander) CmdName() string {
+	return w.cmdName()
+}
+
+func (w wrapNamerDescriberFlaggerSubCommander) Description() string {
+	return w.description()
+}
+
+func (w wrapNamerDescriberFlaggerSubCommander) Flags() (*flag.FlagSet, error) {
+	return w.flags()
+}
+
+func (w wrapNamerDescriberFlaggerSubCommander) SubCommands() []Commander {
+	return w.subCommands()
+}
+
+type wrapAliaserDescriberFlaggerSubCommander struct{ *wrapper }
+
+func (w wrapAliaserDescriberFlaggerSubCommander) CmdAliases() []string {
+	return w.cmdAliases()
+}
+
+func (w wrapAliaserDescriberFlaggerSubCommander) Description() string {
+	return w.description()
+}
+
+func (w wrapAliaserDescriberFlaggerSubCommander) Flags() (*flag.FlagSet, error) {
+	return w.flags()
+}
+
+func (w wrapAliaserDescriberFlaggerSubCommander) SubCommands() []Commander {
+	return w.subCommands()
+}
+
+type wrapNamerAliaserDescriberFlaggerSubCommander struct{ *wrapper }
+
+func (w wrapNamerAliaserDescriberFlaggerSubCommander) CmdName() string {
+	return w.cmdName()
+}
+
+func (w wrapNamerAliaserDescriberFlaggerSubCommander) CmdAliases() []string {
+	return w.cmdAliases()
+}
+
+func (w wrapNamerAliaserDescriberFlaggerSubCommander) Description() string {
+	return w.description()
+}
+
+func (w wrapNamerAliaserDescriberFlaggerSubCommander) Flags() (*flag.FlagSet, error) {
+	return w.flags()
+}
+
+func (w wrapNamerAliaserDescriberFlaggerSubCommander) SubCommands() []Commander {
+	return w.subCommands()
+}
@@ -0,0 +1,289 @@
+package plugcmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/markbates/iox"
+	"github.com/markbates/plugins"
+	"github.com/markbates/plugins/plugtest"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Wrap_Interfaces(t *testing.T) {
+	t.Parallel()
+
+	leaf := &runCmd{name: "leaf"}
+
+	table := []struct {
+		name string
+		cmd  Commander
+	}{
+		{name: "scoped", cmd: scopedRunCmd{runCmd: leaf}},
+		{name: "namer", cmd: leaf},
+		{name: "aliaser", cmd: aliaser{"x"}},
+		{name: "sub", cmd: runSubCmd{leaf}},
+		{name: "flags", cmd: runFlagCmd{runSubCmd{leaf}}},
+		{name: "all", cmd: cmd{}},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			r := require.New(t)
+
+			w := Wrap(tt.cmd)
+			r.Equal(tt.cmd.PluginName(), w.PluginName())
+			r.Equal(tt.cmd, Unwrap(w))
+
+			_, ok := tt.cmd.(Namer)
+			_, wok := w.(Namer)
+			r.Equal(ok, wok, "Namer")
+
+			_, ok = tt.cmd.(Aliaser)
+			_, wok = w.(Aliaser)
+			r.Equal(ok, wok, "Aliaser")
+
+			_, ok = tt.cmd.(Describer)
+			_, wok = w.(Describer)
+			r.Equal(ok, wok, "Describer")
+
+			_, ok = tt.cmd.(Flagger)
+			_, wok = w.(Flagger)
+			r.Equal(ok, wok, "Flagger")
+
+			_, ok = tt.cmd.(SubCommander)
+			_, wok = w.(SubCommander)
+			r.Equal(ok, wok, "SubCommander")
+		})
+	}
+}
+
+func Test_Wrap_Order(t *testing.T) {
+	t.Parallel()
+	r := require.New(t)
+
+	var log []string
+	mw := func(name string) Middleware {
+		return func(cmd Commander, next CommanderFn) CommanderFn {
+			return func(ctx context.Context, root string, args []string) error {
+				log = append(log, name+" "+cmdName(cmd))
+				return next(ctx, root, args)
+			}
+		}
+	}
+
+	c := &runCmd{
+		name: "c",
+		fn: func(args []string) error {
+			log = append(log, "main")
+			return nil
+		},
+	}
+
+	w := Wrap(c, mw("first"), nil, mw("second"))
+	r.NoError(w.Main(context.Background(), "", nil))
+	r.Equal([]string{"first c", "second c", "main"}, log)
+}
+
+func Test_Wrap_Run(t *testing.T) {
+	t.Parallel()
+	r := require.New(t)
+
+	plugs, bb, called, _ := runTree(t)
+
+	var wrapped int
+	count := func(cmd Commander, next CommanderFn) CommanderFn {
+		return func(ctx context.Context, root string, args []string) error {
+			wrapped++
+			return next(ctx, root, args)
+		}
+	}
+
+	a := plugs[0].(runSubCmd)
+	b := a.subs[0].(runFlagCmd)
+	b.subs = []Commander{Wrap(b.subs[0], count)}
+
+	plugs = plugins.Plugins{Wrap(a, count), plugs[1]}
+
+	r.NotNil(Find("a", plugs))
+
+	err := Run(context.Background(), "", []string{"a", "b", "-v", "c", "x"}, plugs)
+	r.NoError(err)
+	r.Equal([]string{"c x"}, *called)
+	r.Equal(1, wrapped)
+
+	r.NoError(Print(&bb.Out, Find("a", plugs)))
+	r.Contains(bb.Out.String(), "$ a")
+	r.Contains(bb.Out.String(), "Available Commands:")
+}
+
+func Test_Wrap_Print(t *testing.T) {
+	t.Parallel()
+	r := require.New(t)
+
+	exp := &bytes.Buffer{}
+	r.NoError(Print(exp, cmd{}))
+
+	act := &bytes.Buffer{}
+	r.NoError(Print(act, Wrap(cmd{}, Recover())))
+
+	r.Equal(exp.String(), act.String())
+}
+
+func Test_Wrap_ArgsSpec(t *testing.T) {
+	t.Parallel()
+	r := require.New(t)
+
+	var ran bool
+	c := argsCmd{
+		runCmd: &runCmd{
+			name: "copy",
+			fn: func(args []string) error {
+				ran = true
+				return nil
+			},
+		},
+		specs: []Arg{Required("src")},
+	}
+
+	bb := &iox.Buffer{}
+	plugs := plugins.Plugins{Wrap(c), &plugtest.IO{IO: bb.IO()}}
+
+	err := Run(context.Background(), "", []string{"copy"}, plugs)
+	r.ErrorIs(err, ErrUsage)
+	r.False(ran)
+	r.Contains(bb.Err.String(), "copy <src>")
+
+	r.NoError(Run(context.Background(), "", []string{"copy", "a"}, plugs))
+	r.True(ran)
+}
+
+func Test_Wrap_Setup(t *testing.T) {
+	t.Parallel()
+	r := require.New(t)
+
+	v := &Version{}
+	plugs := plugins.Plugins{Wrap(v), Wrap(&runCmd{name: "x"})}
+
+	bb := &iox.Buffer{}
+	r.NoError(plugs.SetStdio(bb.IO()))
+	r.NoError(plugs.WithPlugins(plugs.PluginFeeder()))
+	r.True(v.ioSet)
+	r.NotNil(v.feeder)
+
+	bb2 := &bytes.Buffer{}
+	r.NoError(printInfo(bb2, "", plugs))
+	r.Contains(bb2.String(), "Type:        *github.com/markbates/plugins/plugcmd.Version\n")
+	r.Contains(bb2.String(), "Type:        *github.com/markbates/plugins/plugcmd.runCmd\n  Available:   yes\n  IO:          n/a\n")
+}
+
+type srvCmd struct {
+	*runCmd
+	stopped bool
+}
+
+func (c *srvCmd) PluginAvailable(root string) bool {
+	return false
+}
+
+func (c *srvCmd) PluginRequires() ([]string, []string) {
+	return []string{"db"}, nil
+}
+
+func (c *srvCmd) Stop(ctx context.Context) error {
+	c.stopped = true
+	return nil
+}
+
+func Test_Wrap_Plugins(t *testing.T) {
+	t.Parallel()
+	r := require.New(t)
+
+	c := &srvCmd{runCmd: &runCmd{name: "srv"}}
+	w := Wrap(c, Recover())
+
+	plugs := plugins.Plugins{w}
+	r.Empty(plugs.Available(""))
+
+	err := plugs.Validate()
+	r.Error(err)
+	r.Contains(err.Error(), "plugin run/srv requires missing plugin db")
+
+	plugs = plugins.Plugins{w, stringPlug("db")}
+	r.NoError(plugs.Validate())
+	r.NoError(plugs.Stop(context.Background()))
+	r.True(c.stopped)
+
+	// only the interfaces cmd implements
+	_, ok := plugins.As[plugins.IOSetable](w)
+	r.False(ok)
+	_, ok = w.(plugins.Needer)
+	r.False(ok)
+
+	u, ok := w.(plugins.Unwrapper)
+	r.True(ok)
+	r.Equal(c, u.UnwrapPlugin())
+}
+
+func Test_Recover(t *testing.T) {
+	t.Parallel()
+	r := require.New(t)
+
+	boom := errors.New("boom")
+	c := &runCmd{
+		name: "c",
+		fn: func(args []string) error {
+			panic(boom)
+		},
+	}
+
+	err := Wrap(c, Recover()).Main(context.Background(), "", nil)
+	r.ErrorIs(err, boom)
+	r.Equal("c: panic: boom", err.Error())
+
+	var pe *PanicError
+	r.True(errors.As(err, &pe))
+	r.NotEmpty(pe.Stack)
+}
+
+func Test_Timing(t *testing.T) {
+	t.Parallel()
+	r := require.New(t)
+
+	bb := &bytes.Buffer{}
+	logger := slog.New(slog.NewTextHandler(bb, nil))
+
+	c := &runCmd{
+		name: "c",
+		fn: func(args []string) error {
+			return errors.New("boom")
+		},
+	}
+
+	err := Wrap(c, Timing(logger)).Main(context.Background(), "", nil)
+	r.Error(err)
+
+	out := bb.String()
+	r.Contains(out, "level=ERROR")
+	r.Contains(out, `msg="command finished"`)
+	r.Contains(out, "command=c")
+	r.Contains(out, "duration=")
+	r.Contains(out, "error=boom")
+}
+
+func Test_Timeout(t *testing.T) {
+	t.Parallel()
+	r := require.New(t)
+
+	var fn CommanderFn = func(ctx context.Context, root string, args []string) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+
+	err := Wrap(fn, Timeout(time.Millisecond)).Main(context.Background(), "", nil)
+	r.ErrorIs(err, context.DeadlineExceeded)
+}
@@ -43,14 +43,17 @@
 //   - Feeder/Needer: Plugin communication and dependency injection
 //   - AvailabilityChecker: Runtime availability checking
 //   - IOSetable/FSSetable: I/O and filesystem configuration
+//   - Initializer/Starter/Stopper: Resource lifecycle management
+//   - Requirer: Declared dependencies between plugins
+//   - Unwrapper: Plugins that decorate another plugin
 //
 // See the plugcmd subpackage for command-line specific plugin interfaces.
 package plugins
 
 import (
+	"errors"
 	"fmt"
 	"io/fs"
-	"log/slog"
 )
 
 var _ FSSetable = Plugins{}
@@ -90,58 +93,98 @@ func (plugs Plugins) ScopedPlugins() Plugins {
 }
 
 // SetStdio for those plugins that implement
-// IOSetable.
+// IOSetable, in dependency order. It stops at the
+// first plugin that fails, see ContinueOnError to
+// attempt every plugin.
 func (plugs Plugins) SetStdio(io IO) error {
-	ios := ByType[IOSetable](plugs)
-
-	for _, p := range ios {
-		if err := p.SetStdio(io); err != nil {
-			slog.Error("failed to set stdio for plugin",
-				"plugin", p.PluginName(),
-				"error", err)
-			return fmt.Errorf("failed to set stdio for plugin %s: %w", p.PluginName(), err)
-		}
+	return plugs.setStdio(io, false)
+}
+
+func (plugs Plugins) setStdio(io IO, cont bool) error {
+	sorted, serr := plugs.sorted(cont)
+	if serr != nil && !cont {
+		return serr
 	}
 
-	return nil
+	ios := ByType[IOSetable](sorted)
+
+	err := each(ios, OpSetStdio, cont, func(p IOSetable) error {
+		return p.SetStdio(io)
+	})
+	return joinSortErr(serr, err)
 }
 
 // WithPlugins will call any Needer plugins with the
-// Feeder function.
+// Feeder function, in dependency order. It stops at
+// the first plugin that fails, see ContinueOnError
+// to attempt every plugin.
 func (plugs Plugins) WithPlugins(fn FeederFn) error {
+	return plugs.withPlugins(fn, false)
+}
+
+func (plugs Plugins) withPlugins(fn FeederFn, cont bool) error {
 	if fn == nil {
 		return fmt.Errorf("no FeederFn provided")
 	}
 
-	needers := ByType[Needer](plugs)
-
-	for _, n := range needers {
-		if err := n.WithPlugins(fn); err != nil {
-			return fmt.Errorf("failed to set plugins for needer %s: %w", n.PluginName(), err)
-		}
+	sorted, serr := plugs.sorted(cont)
+	if serr != nil && !cont {
+		return serr
 	}
 
-	return nil
+	needers := ByType[Needer](sorted)
+
+	err := each(needers, OpWithPlugins, cont, func(n Needer) error {
+		return n.WithPlugins(fn)
+	})
+	return joinSortErr(serr, err)
 }
 
-// SetFS for those plugins that implement FSSetable.
+// SetFS for those plugins that implement FSSetable,
+// in dependency order. It stops at the first plugin
+// that fails, see ContinueOnError to attempt every
+// plugin.
 func (plugs Plugins) SetFileSystem(fs fs.FS) error {
+	return plugs.setFileSystem(fs, false)
+}
+
+func (plugs Plugins) setFileSystem(fs fs.FS, cont bool) error {
 	if fs == nil {
 		return fmt.Errorf("no fs.FS provided")
 	}
 
-	fsps := ByType[FSSetable](plugs)
+	sorted, serr := plugs.sorted(cont)
+	if serr != nil && !cont {
+		return serr
+	}
 
-	for _, p := range fsps {
-		if err := p.SetFileSystem(fs); err != nil {
-			slog.Error("failed to set filesystem for plugin",
-				"plugin", p.PluginName(),
-				"error", err)
-			return fmt.Errorf("failed to set filesystem for plugin %s: %w", p.PluginName(), err)
-		}
+	fsps := ByType[FSSetable](sorted)
+
+	err := each(fsps, OpSetFileSystem, cont, func(p FSSetable) error {
+		return p.SetFileSystem(fs)
+	})
+	return joinSortErr(serr, err)
+}
+
+// sorted returns the plugins in dependency order. If they
+// can not be sorted, and cont is true, the plugins are
+// returned in collection order, along with the sort error,
+// the same as Stop.
+func (plugs Plugins) sorted(cont bool) (Plugins, error) {
+	sorted, err := plugs.Sorted()
+	if err != nil && cont {
+		return plugs, err
 	}
+	return sorted, err
+}
 
-	return nil
+// joinSortErr joins the sort error, if any,
+// with the errors of the plugins.
+func joinSortErr(serr error, err error) error {
+	if serr == nil {
+		return err
+	}
+	return errors.Join(serr, err)
 }
 
 // Find plugins using the given Finder.
@@ -158,7 +201,7 @@ func (plugs Plugins) Available(root string) Plugins {
 	var res Plugins
 
 	for _, p := range plugs {
-		ac, ok := p.(AvailabilityChecker)
+		ac, ok := As[AvailabilityChecker](p)
 		if !ok {
 			res = append(res, p)
 			continue
@@ -177,8 +220,9 @@ func (plugs Plugins) PluginName() string {
 }
 
 // Validate checks the plugins collection for common issues.
-// It verifies that no duplicate plugin names exist and that
-// all plugins have valid names.
+// It verifies that no duplicate plugin names exist, that
+// all plugins have valid names, and that the dependencies
+// declared by Requirer plugins are present and acyclic.
 func (plugs Plugins) Validate() error {
 	if len(plugs) == 0 {
 		return fmt.Errorf("no plugins provided")
@@ -200,6 +244,11 @@ func (plugs Plugins) Validate() error {
 		}
 		names[name] = true
 	}
+
+	if _, err := plugs.Sorted(); err != nil {
+		return err
+	}
+
 	return nil
 }
 
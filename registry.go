@@ -0,0 +1,206 @@
+package plugins
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+)
+
+var _ Feeder = &Registry{}
+var _ Scoper = &Registry{}
+
+// Registry is a concurrency-safe owner of a Plugins
+// collection. It can be mutated at runtime with Register,
+// Unregister, and Replace. Every mutation is validated
+// before it is applied, and any subscribed Needer plugins
+// are re-fed once membership has changed.
+type Registry struct {
+	plugs Plugins
+	subs  []*subscription
+
+	mu sync.RWMutex
+}
+
+// NewRegistry returns a Registry containing the given
+// plugins. An error is returned if the plugins do not
+// pass validation.
+func NewRegistry(plugs ...Plugin) (*Registry, error) {
+	reg := &Registry{}
+	if err := reg.Register(plugs...); err != nil {
+		return nil, err
+	}
+	return reg, nil
+}
+
+// PluginName implements Plugin.
+func (reg *Registry) PluginName() string {
+	return fmt.Sprintf("%T", reg)
+}
+
+// Plugins returns a snapshot of the plugins currently
+// in the registry. Changes to the returned collection
+// do not affect the registry.
+func (reg *Registry) Plugins() Plugins {
+	if reg == nil {
+		return nil
+	}
+
+	reg.mu.RLock()
+	defer reg.mu.RUnlock()
+
+	return slices.Clone(reg.plugs)
+}
+
+// PluginFeeder returns a FeederFn that returns a fresh
+// snapshot of the registry each time it is called.
+func (reg *Registry) PluginFeeder() FeederFn {
+	return reg.Plugins
+}
+
+// ScopedPlugins implements Scoper, returning a snapshot
+// of the registry.
+func (reg *Registry) ScopedPlugins() Plugins {
+	return reg.Plugins()
+}
+
+// Register adds the given plugins to the registry.
+// If the resulting collection fails validation, for
+// example because of a duplicate name, the registry
+// is left unchanged and the error is returned.
+func (reg *Registry) Register(plugs ...Plugin) error {
+	if reg == nil {
+		return fmt.Errorf("registry cannot be nil")
+	}
+
+	if len(plugs) == 0 {
+		return nil
+	}
+
+	return reg.mutate(func(cur Plugins) (Plugins, error) {
+		return append(slices.Clone(cur), plugs...), nil
+	})
+}
+
+// Unregister removes the plugin with the given name
+// from the registry. An error is returned if no plugin
+// with that name is registered.
+func (reg *Registry) Unregister(name string) error {
+	if reg == nil {
+		return fmt.Errorf("registry cannot be nil")
+	}
+
+	return reg.mutate(func(cur Plugins) (Plugins, error) {
+		i := slices.IndexFunc(cur, func(p Plugin) bool {
+			return p.PluginName() == name
+		})
+		if i < 0 {
+			return nil, fmt.Errorf("plugin not registered: %s", name)
+		}
+
+		return slices.Delete(slices.Clone(cur), i, i+1), nil
+	})
+}
+
+// Replace swaps the contents of the registry for
+// the given plugins.
+func (reg *Registry) Replace(plugs Plugins) error {
+	if reg == nil {
+		return fmt.Errorf("registry cannot be nil")
+	}
+
+	return reg.mutate(func(cur Plugins) (Plugins, error) {
+		return slices.Clone(plugs), nil
+	})
+}
+
+// subscription is a Needer subscribed to a Registry.
+// Each call to Subscribe has its own subscription, so
+// it can be removed without comparing Needers, which
+// may not be comparable.
+type subscription struct {
+	Needer
+}
+
+// Subscribe registers a Needer to be fed the registry's
+// PluginFeeder now, and again after every change in
+// membership. The returned cancel func stops the Needer
+// from being notified. It is safe to call more than once.
+//
+//	cancel, err := reg.Subscribe(n)
+//	if err != nil {
+//		return err
+//	}
+//	defer cancel()
+func (reg *Registry) Subscribe(n Needer) (func(), error) {
+	if reg == nil {
+		return nil, fmt.Errorf("registry cannot be nil")
+	}
+
+	if n == nil {
+		return nil, fmt.Errorf("no Needer provided")
+	}
+
+	if err := n.WithPlugins(reg.PluginFeeder()); err != nil {
+		return nil, NewPluginError(OpWithPlugins, n, err)
+	}
+
+	sub := &subscription{Needer: n}
+
+	reg.mu.Lock()
+	reg.subs = append(reg.subs, sub)
+	reg.mu.Unlock()
+
+	cancel := func() {
+		reg.mu.Lock()
+		defer reg.mu.Unlock()
+
+		reg.subs = slices.DeleteFunc(reg.subs, func(s *subscription) bool {
+			return s == sub
+		})
+	}
+
+	return cancel, nil
+}
+
+// mutate applies fn to the current collection under the
+// write lock, validates the result, and, if it is valid,
+// stores it and notifies subscribers. Subscribers are
+// notified outside of the lock so they may safely call
+// back into the registry.
+func (reg *Registry) mutate(fn func(cur Plugins) (Plugins, error)) error {
+	reg.mu.Lock()
+
+	next, err := fn(reg.plugs)
+	if err != nil {
+		reg.mu.Unlock()
+		return err
+	}
+
+	if len(next) > 0 {
+		if err := next.Validate(); err != nil {
+			reg.mu.Unlock()
+			return err
+		}
+	}
+
+	reg.plugs = next
+	subs := slices.Clone(reg.subs)
+
+	reg.mu.Unlock()
+
+	return reg.notify(subs)
+}
+
+func (reg *Registry) notify(subs []*subscription) error {
+	fn := reg.PluginFeeder()
+
+	var errs []error
+	for _, s := range subs {
+		if err := s.WithPlugins(fn); err != nil {
+			errs = append(errs, NewPluginError(OpWithPlugins, s.Needer, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
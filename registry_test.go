@@ -0,0 +1,198 @@
+package plugins_test
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	. "github.com/markbates/plugins"
+	"github.com/markbates/plugins/plugtest"
+	"github.com/stretchr/testify/require"
+)
+
+type countingNeeder struct {
+	name  string
+	calls int
+	last  Plugins
+
+	mu sync.Mutex
+}
+
+func (c *countingNeeder) PluginName() string {
+	return c.name
+}
+
+func (c *countingNeeder) WithPlugins(fn FeederFn) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.calls++
+	c.last = fn()
+	return nil
+}
+
+func Test_NewRegistry(t *testing.T) {
+	t.Parallel()
+	r := require.New(t)
+
+	reg, err := NewRegistry(plugtest.StringPlugin("a"), plugtest.StringPlugin("b"))
+	r.NoError(err)
+	r.Equal([]string{"a", "b"}, reg.Plugins().Names())
+
+	_, err = NewRegistry(plugtest.StringPlugin("a"), plugtest.StringPlugin("a"))
+	r.Error(err)
+	r.Contains(err.Error(), "duplicate plugin name: a")
+}
+
+func Test_Registry_Register(t *testing.T) {
+	t.Parallel()
+	r := require.New(t)
+
+	reg, err := NewRegistry()
+	r.NoError(err)
+	r.Empty(reg.Plugins())
+
+	r.NoError(reg.Register(plugtest.StringPlugin("a")))
+	r.NoError(reg.Register(plugtest.StringPlugin("b")))
+
+	err = reg.Register(plugtest.StringPlugin("a"))
+	r.Error(err)
+	r.Contains(err.Error(), "duplicate plugin name: a")
+
+	r.Equal([]string{"a", "b"}, reg.Plugins().Names())
+}
+
+func Test_Registry_Unregister(t *testing.T) {
+	t.Parallel()
+	r := require.New(t)
+
+	reg, err := NewRegistry(plugtest.StringPlugin("a"), plugtest.StringPlugin("b"))
+	r.NoError(err)
+
+	r.NoError(reg.Unregister("a"))
+	r.Equal([]string{"b"}, reg.Plugins().Names())
+
+	err = reg.Unregister("a")
+	r.Error(err)
+	r.Contains(err.Error(), "plugin not registered: a")
+
+	r.NoError(reg.Unregister("b"))
+	r.Empty(reg.Plugins())
+}
+
+func Test_Registry_Replace(t *testing.T) {
+	t.Parallel()
+	r := require.New(t)
+
+	reg, err := NewRegistry(plugtest.StringPlugin("a"))
+	r.NoError(err)
+
+	r.NoError(reg.Replace(Plugins{plugtest.StringPlugin("x"), plugtest.StringPlugin("y")}))
+	r.Equal([]string{"x", "y"}, reg.Plugins().Names())
+
+	err = reg.Replace(Plugins{nil})
+	r.Error(err)
+	r.Equal([]string{"x", "y"}, reg.Plugins().Names())
+}
+
+func Test_Registry_Snapshot(t *testing.T) {
+	t.Parallel()
+	r := require.New(t)
+
+	reg, err := NewRegistry(plugtest.StringPlugin("a"))
+	r.NoError(err)
+
+	fn := reg.PluginFeeder()
+	snap := fn()
+	snap[0] = plugtest.StringPlugin("mutated")
+
+	r.Equal([]string{"a"}, reg.Plugins().Names())
+
+	r.NoError(reg.Register(plugtest.StringPlugin("b")))
+	r.Equal([]string{"a", "b"}, fn().Names())
+	r.Equal(fn(), reg.ScopedPlugins())
+}
+
+func Test_Registry_Subscribe(t *testing.T) {
+	t.Parallel()
+	r := require.New(t)
+
+	reg, err := NewRegistry(plugtest.StringPlugin("a"))
+	r.NoError(err)
+
+	n := &countingNeeder{name: "counter"}
+	cancel, err := reg.Subscribe(n)
+	r.NoError(err)
+	r.Equal(1, n.calls)
+	r.Equal([]string{"a"}, n.last.Names())
+
+	r.NoError(reg.Register(plugtest.StringPlugin("b")))
+	r.Equal(2, n.calls)
+	r.Equal([]string{"a", "b"}, n.last.Names())
+
+	// failed mutations do not notify
+	r.Error(reg.Register(plugtest.StringPlugin("b")))
+	r.Equal(2, n.calls)
+
+	r.NoError(reg.Unregister("a"))
+	r.Equal(3, n.calls)
+	r.Equal([]string{"b"}, n.last.Names())
+
+	cancel()
+	cancel()
+	r.NoError(reg.Register(plugtest.StringPlugin("c")))
+	r.Equal(3, n.calls)
+
+	_, err = reg.Subscribe(nil)
+	r.Error(err)
+}
+
+func Test_Registry_Subscribe_Uncomparable(t *testing.T) {
+	t.Parallel()
+	r := require.New(t)
+
+	reg, err := NewRegistry(plugtest.StringPlugin("a"))
+	r.NoError(err)
+
+	// Plugins is a Needer that can not be compared
+	plugs := Plugins{&countingNeeder{name: "counter"}}
+	other := Plugins{&countingNeeder{name: "other"}}
+
+	cancel, err := reg.Subscribe(plugs)
+	r.NoError(err)
+
+	_, err = reg.Subscribe(other)
+	r.NoError(err)
+
+	r.NotPanics(cancel)
+
+	r.NoError(reg.Register(plugtest.StringPlugin("b")))
+	r.Equal(1, plugs[0].(*countingNeeder).calls)
+	r.Equal(2, other[0].(*countingNeeder).calls)
+}
+
+func Test_Registry_Concurrent(t *testing.T) {
+	t.Parallel()
+	r := require.New(t)
+
+	reg, err := NewRegistry()
+	r.NoError(err)
+
+	n := &countingNeeder{name: "counter"}
+	_, err = reg.Subscribe(n)
+	r.NoError(err)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_ = reg.Register(plugtest.StringPlugin(fmt.Sprintf("p%d", i)))
+			_ = reg.Plugins()
+		}(i)
+	}
+	wg.Wait()
+
+	r.Len(reg.Plugins(), 50)
+	r.Equal(51, n.calls)
+}
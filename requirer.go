@@ -0,0 +1,119 @@
+package plugins
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Requirer can be implemented to declare the plugins,
+// by name, that a plugin depends on. Required plugins
+// must be present in the collection, optional plugins
+// are only used for ordering when they are present.
+type Requirer interface {
+	Plugin
+	PluginRequires() (required []string, optional []string)
+}
+
+// CycleError is returned when the dependencies declared
+// by Requirer plugins form a cycle.
+type CycleError struct {
+	// Path is the list of plugin names that form the
+	// cycle. The first and last names are the same.
+	Path []string
+}
+
+func (e *CycleError) Error() string {
+	return fmt.Sprintf("dependency cycle: %s", strings.Join(e.Path, " -> "))
+}
+
+// Sorted returns a copy of the collection ordered so
+// that every plugin comes after the plugins it requires.
+// Plugins without dependencies keep their collection order.
+// An error is returned if a required plugin is missing, or
+// if the dependencies form a cycle.
+func (plugs Plugins) Sorted() (Plugins, error) {
+	if len(ByType[Requirer](plugs)) == 0 {
+		return slices.Clone(plugs), nil
+	}
+
+	index := make(map[string]int, len(plugs))
+	for i, p := range plugs {
+		if p == nil {
+			continue
+		}
+		if _, ok := index[p.PluginName()]; !ok {
+			index[p.PluginName()] = i
+		}
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+
+	state := make([]int, len(plugs))
+	res := make(Plugins, 0, len(plugs))
+	var stack []string
+
+	var visit func(i int) error
+	visit = func(i int) error {
+		switch state[i] {
+		case visited:
+			return nil
+		case visiting:
+			name := plugs[i].PluginName()
+			start := slices.Index(stack, name)
+			path := append(slices.Clone(stack[start:]), name)
+			return &CycleError{Path: path}
+		}
+
+		p := plugs[i]
+		state[i] = visiting
+
+		if r, ok := As[Requirer](p); ok {
+			stack = append(stack, p.PluginName())
+
+			req, opt := r.PluginRequires()
+			for _, n := range req {
+				j, ok := index[n]
+				if !ok {
+					return fmt.Errorf("plugin %s requires missing plugin %s", p.PluginName(), n)
+				}
+				if err := visit(j); err != nil {
+					return err
+				}
+			}
+
+			for _, n := range opt {
+				j, ok := index[n]
+				if !ok {
+					continue
+				}
+				if err := visit(j); err != nil {
+					return err
+				}
+			}
+
+			stack = stack[:len(stack)-1]
+		}
+
+		state[i] = visited
+		res = append(res, p)
+		return nil
+	}
+
+	for i := range plugs {
+		if plugs[i] == nil {
+			state[i] = visited
+			res = append(res, nil)
+			continue
+		}
+		if err := visit(i); err != nil {
+			return nil, err
+		}
+	}
+
+	return res, nil
+}
@@ -0,0 +1,146 @@
+package plugins_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/markbates/iox"
+	. "github.com/markbates/plugins"
+	"github.com/markbates/plugins/plugtest"
+	"github.com/stretchr/testify/require"
+)
+
+type requirer struct {
+	name     string
+	required []string
+	optional []string
+}
+
+func (r requirer) PluginName() string {
+	return r.name
+}
+
+func (r requirer) PluginRequires() ([]string, []string) {
+	return r.required, r.optional
+}
+
+type orderedIO struct {
+	requirer
+	log *lifecycleLog
+}
+
+func (o orderedIO) SetStdio(io IO) error {
+	o.log.add(o.name)
+	return nil
+}
+
+func (o orderedIO) Start(ctx context.Context) error {
+	o.log.add("start " + o.name)
+	return nil
+}
+
+func (o orderedIO) Stop(ctx context.Context) error {
+	o.log.add("stop " + o.name)
+	return nil
+}
+
+func Test_Plugins_Sorted(t *testing.T) {
+	t.Parallel()
+	r := require.New(t)
+
+	plugs := Plugins{
+		requirer{name: "app", required: []string{"db", "cache"}},
+		plugtest.StringPlugin("standalone"),
+		requirer{name: "cache", optional: []string{"metrics", "missing"}},
+		requirer{name: "db", required: []string{"config"}},
+		plugtest.StringPlugin("config"),
+		plugtest.StringPlugin("metrics"),
+	}
+
+	sorted, err := plugs.Sorted()
+	r.NoError(err)
+
+	exp := []string{"config", "db", "metrics", "cache", "app", "standalone"}
+	r.Equal(exp, sorted.Names())
+
+	// the original collection is untouched
+	r.Equal("app", plugs[0].PluginName())
+}
+
+func Test_Plugins_Sorted_NoRequirers(t *testing.T) {
+	t.Parallel()
+	r := require.New(t)
+
+	plugs := Plugins{
+		plugtest.StringPlugin("b"),
+		plugtest.StringPlugin("a"),
+	}
+
+	sorted, err := plugs.Sorted()
+	r.NoError(err)
+	r.Equal(plugs, sorted)
+}
+
+func Test_Plugins_Sorted_Missing(t *testing.T) {
+	t.Parallel()
+	r := require.New(t)
+
+	plugs := Plugins{
+		requirer{name: "app", required: []string{"db"}},
+	}
+
+	_, err := plugs.Sorted()
+	r.Error(err)
+	r.Contains(err.Error(), "plugin app requires missing plugin db")
+
+	err = plugs.Validate()
+	r.Error(err)
+	r.Contains(err.Error(), "plugin app requires missing plugin db")
+}
+
+func Test_Plugins_Sorted_Cycle(t *testing.T) {
+	t.Parallel()
+	r := require.New(t)
+
+	plugs := Plugins{
+		plugtest.StringPlugin("x"),
+		requirer{name: "a", required: []string{"x", "b"}},
+		requirer{name: "b", optional: []string{"c"}},
+		requirer{name: "c", required: []string{"a"}},
+	}
+
+	_, err := plugs.Sorted()
+	r.Error(err)
+
+	var ce *CycleError
+	r.True(errors.As(err, &ce))
+	r.Equal([]string{"a", "b", "c", "a"}, ce.Path)
+	r.Equal("dependency cycle: a -> b -> c -> a", err.Error())
+
+	r.ErrorAs(plugs.Validate(), &ce)
+}
+
+func Test_Plugins_Sorted_Drives_Setup(t *testing.T) {
+	t.Parallel()
+	r := require.New(t)
+
+	log := &lifecycleLog{}
+	plugs := Plugins{
+		orderedIO{requirer: requirer{name: "b", required: []string{"a"}}, log: log},
+		orderedIO{requirer: requirer{name: "a"}, log: log},
+	}
+
+	r.NoError(plugs.SetStdio(iox.Discard()))
+	r.NoError(plugs.Start(context.Background()))
+	r.NoError(plugs.Stop(context.Background()))
+
+	exp := []string{"a", "b", "start a", "start b", "stop b", "stop a"}
+	r.Equal(exp, log.events)
+
+	bad := Plugins{
+		orderedIO{requirer: requirer{name: "b", required: []string{"a"}}, log: log},
+	}
+	r.Error(bad.SetStdio(iox.Discard()))
+	r.Error(bad.Start(context.Background()))
+}